@@ -0,0 +1,169 @@
+package codec
+
+import (
+	"context"
+	"io"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+)
+
+type (
+	// Codec pairs an encoder and a decoder for one type, so the values are encoded and decoded without going
+	// through any
+	Codec[T any] struct {
+		encoder gojsonencoder.Encoder
+		decoder gojsondecoder.Decoder
+	}
+)
+
+// NewCodec creates a new Codec instance
+//
+// Parameters:
+//
+//   - encoder: the encoder used to encode the values
+//   - decoder: the decoder used to decode the values
+//
+// Returns:
+//
+//   - *Codec[T]: the new Codec instance
+//   - error: the error if the encoder or the decoder is nil
+func NewCodec[T any](
+	encoder gojsonencoder.Encoder,
+	decoder gojsondecoder.Decoder,
+) (*Codec[T], error) {
+	// Check if the encoder is nil
+	if encoder == nil {
+		return nil, gojsonencoder.ErrNilEncoder
+	}
+
+	// Check if the decoder is nil
+	if decoder == nil {
+		return nil, gojsondecoder.ErrNilDecoder
+	}
+
+	return &Codec[T]{
+		encoder: encoder,
+		decoder: decoder,
+	}, nil
+}
+
+// Encoder returns the encoder of the codec, so it can be given to the helpers that take an encoder
+//
+// Returns:
+//
+//   - gojsonencoder.Encoder: the encoder
+func (c Codec[T]) Encoder() gojsonencoder.Encoder {
+	return c.encoder
+}
+
+// Decoder returns the decoder of the codec, so it can be given to the helpers that take a decoder
+//
+// Returns:
+//
+//   - gojsondecoder.Decoder: the decoder
+func (c Codec[T]) Decoder() gojsondecoder.Decoder {
+	return c.decoder
+}
+
+// Encode encodes the value
+//
+// Parameters:
+//
+//   - value: The value to encode
+//
+// Returns:
+//
+//   - []byte: The encoded value
+//   - error: The error if any
+func (c Codec[T]) Encode(value T) ([]byte, error) {
+	return c.encoder.Encode(value)
+}
+
+// EncodeAndWrite encodes the value and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded value to
+//   - beforeWriteFn: The function to call before writing the value
+//   - value: The value to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (c Codec[T]) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	value T,
+) error {
+	return c.encoder.EncodeAndWrite(writer, beforeWriteFn, value)
+}
+
+// EncodeAndWriteContext encodes the value and writes it to the writer, stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the encoded value to
+//   - beforeWriteFn: The function to call before writing the value
+//   - value: The value to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (c Codec[T]) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	value T,
+) error {
+	return gojsonencoder.EncodeAndWriteContext(ctx, c.encoder, writer, beforeWriteFn, value)
+}
+
+// Decode decodes the body into a new value. If the type parameter is a pointer, the pointed value is allocated
+//
+// Parameters:
+//
+//   - body: The body to decode, an io.Reader, a string or a []byte
+//
+// Returns:
+//
+//   - T: The decoded value, it can be partially decoded if there is an error, such as in the collect mode
+//   - error: The error if any
+func (c Codec[T]) Decode(body any) (T, error) {
+	return gojsondecoder.Decode[T](c.decoder, body)
+}
+
+// DecodeReader decodes the body from a reader into a new value
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - T: The decoded value, it can be partially decoded if there is an error, such as in the collect mode
+//   - error: The error if any
+func (c Codec[T]) DecodeReader(reader io.Reader) (T, error) {
+	return c.DecodeReaderContext(context.Background(), reader)
+}
+
+// DecodeReaderContext decodes the body from a reader into a new value, stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - T: The decoded value, it can be partially decoded if there is an error, such as in the collect mode
+//   - error: The error if any, the cause of the context if it is done
+func (c Codec[T]) DecodeReaderContext(ctx context.Context, reader io.Reader) (T, error) {
+	// Check the reader
+	if reader == nil {
+		var zero T
+		return zero, gojsondecoder.ErrNilReader
+	}
+	return gojsondecoder.DecodeContext[T](ctx, c.decoder, reader)
+}
@@ -0,0 +1,100 @@
+package codec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func TestNewCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoder gojsonencoder.Encoder
+		decoder gojsondecoder.Decoder
+		wantErr error
+	}{
+		{
+			name:    "pairs the encoder and the decoder",
+			encoder: gojsonencoderjson.NewEncoder(nil),
+			decoder: gojsondecoderjson.NewDecoder(nil),
+		},
+		{
+			name:    "rejects the nil encoder",
+			decoder: gojsondecoderjson.NewDecoder(nil),
+			wantErr: gojsonencoder.ErrNilEncoder,
+		},
+		{
+			name:    "rejects the nil decoder",
+			encoder: gojsonencoderjson.NewEncoder(nil),
+			wantErr: gojsondecoder.ErrNilDecoder,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				codec, err := NewCodec[user](test.encoder, test.decoder)
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("error = %v, want %v", err, test.wantErr)
+				}
+				if test.wantErr == nil && (codec.Encoder() != test.encoder || codec.Decoder() != test.decoder) {
+					t.Error("codec does not keep the encoder and the decoder")
+				}
+			},
+		)
+	}
+}
+
+func TestCodecDecode(t *testing.T) {
+	encoder := gojsonencoderjson.NewEncoder(nil)
+	decoder := gojsondecoderjson.NewDecoder(nil)
+
+	// The values are decoded into a new value of the type
+	valueCodec, err := NewCodec[user](encoder, decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := valueCodec.Decode(`{"name":"gopher"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Name != "gopher" {
+		t.Errorf("name = %q, want %q", value.Name, "gopher")
+	}
+
+	// The pointed value is allocated for the pointer types
+	pointerCodec, err := NewCodec[*user](encoder, decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointer, err := pointerCodec.DecodeReader(strings.NewReader(`{"name":"gopher"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pointer == nil || pointer.Name != "gopher" {
+		t.Errorf("decoded = %+v, want the allocated value", pointer)
+	}
+
+	// The encoded value round trips
+	encoded, err := pointerCodec.Encode(pointer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"name":"gopher"}` {
+		t.Errorf("encoded = %s, want %s", encoded, `{"name":"gopher"}`)
+	}
+
+	// The nil readers are rejected
+	if _, err = pointerCodec.DecodeReader(nil); !errors.Is(err, gojsondecoder.ErrNilReader) {
+		t.Errorf("error = %v, want %v", err, gojsondecoder.ErrNilReader)
+	}
+}
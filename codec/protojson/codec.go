@@ -0,0 +1,48 @@
+package protojson
+
+import (
+	gojsoncodec "github.com/ralvarezdev/go-json/codec"
+	gojsondecoderprotojson "github.com/ralvarezdev/go-json/decoder/protojson"
+	gojsonencoderprotojson "github.com/ralvarezdev/go-json/encoder/protojson"
+)
+
+// NewCodec creates a new Codec for the type parameter with the protojson encoder and decoder. The encoder and
+// decoder mappers are built once for the type, so the values are encoded and decoded without looking them up
+//
+// Parameters:
+//
+//   - encoder: the protojson encoder, nil uses the default encoder
+//   - decoder: the protojson decoder, nil uses the default decoder
+//
+// Returns:
+//
+//   - *gojsoncodec.Codec[T]: the new Codec instance
+//   - error: the error if the type parameter is not a struct or a pointer to a struct, or if a mapper cannot be
+//     built
+func NewCodec[T any](
+	encoder *gojsonencoderprotojson.Encoder,
+	decoder *gojsondecoderprotojson.Decoder,
+) (*gojsoncodec.Codec[T], error) {
+	// Initialize the encoder and the decoder
+	if encoder == nil {
+		encoder = gojsonencoderprotojson.NewEncoder(nil)
+	}
+	if decoder == nil {
+		decoder = gojsondecoderprotojson.NewDecoder(nil)
+	}
+
+	// Build the mappers once
+	encoderMapper, err := gojsonencoderprotojson.NewMapperFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	decoderMapper, err := gojsondecoderprotojson.NewMapperFor[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return gojsoncodec.NewCodec[T](
+		encoder.WithMapper(encoderMapper),
+		decoder.WithMapper(decoderMapper),
+	)
+}
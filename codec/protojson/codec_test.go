@@ -0,0 +1,98 @@
+package protojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	gojsondecoderprotojson "github.com/ralvarezdev/go-json/decoder/protojson"
+)
+
+type fileBody struct {
+	Name string                            `json:"name"`
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+const fileBodyJSON = `{"file":{"name":"a.proto","syntax":"proto3"},"name":"gopher"}`
+
+func TestNewCodecValueAndPointer(t *testing.T) {
+	valueCodec, err := NewCodec[fileBody](nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := valueCodec.Decode(fileBodyJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Name != "gopher" || value.File.GetName() != "a.proto" {
+		t.Errorf("decoded = %+v, want the name and the file", value)
+	}
+
+	// The pointed value is allocated for the pointer types, and the encoded value round trips
+	pointerCodec, err := NewCodec[*fileBody](nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointer, err := pointerCodec.Decode(fileBodyJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pointer == nil || !proto.Equal(pointer.File, value.File) {
+		t.Fatalf("decoded = %+v, want %+v", pointer, value)
+	}
+	encoded, err := pointerCodec.Encode(pointer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare the compacted documents, protojson randomizes its whitespace
+	var compacted bytes.Buffer
+	if err = json.Compact(&compacted, encoded); err != nil {
+		t.Fatal(err)
+	}
+	if compacted.String() != fileBodyJSON {
+		t.Errorf("encoded = %s, want %s", compacted.String(), fileBodyJSON)
+	}
+}
+
+func TestNewCodecNotStruct(t *testing.T) {
+	if _, err := NewCodec[int](nil, nil); err == nil {
+		t.Error("error = nil, want an error for a type that is not a struct")
+	}
+	if _, err := NewCodec[*string](nil, nil); err == nil {
+		t.Error("error = nil, want an error for a pointer to a type that is not a struct")
+	}
+}
+
+func TestNewCodecPrebuiltMapper(t *testing.T) {
+	codec, err := NewCodec[fileBody](nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := gojsondecoderprotojson.NewDecoder(nil)
+
+	// The codec decodes with the mapper built at construction, while the decoder without cache builds a mapper for
+	// each call
+	codecAllocs := testing.AllocsPerRun(
+		100, func() {
+			if _, err := codec.Decode(fileBodyJSON); err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	decoderAllocs := testing.AllocsPerRun(
+		100, func() {
+			var dest fileBody
+			if err := decoder.Decode(fileBodyJSON, &dest); err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	if codecAllocs >= decoderAllocs {
+		t.Errorf("codec allocations = %v, want fewer than the %v of the decoder building its mapper", codecAllocs,
+			decoderAllocs)
+	}
+}
@@ -0,0 +1,30 @@
+package decompress
+
+const (
+	// EncodingIdentity is the content encoding of an uncompressed body
+	EncodingIdentity = "identity"
+
+	// EncodingGzip is the content encoding of a body compressed with gzip
+	EncodingGzip = "gzip"
+
+	// EncodingXGzip is the legacy alias of the gzip content encoding
+	EncodingXGzip = "x-gzip"
+
+	// EncodingDeflate is the content encoding of a body compressed with deflate, either zlib-wrapped or raw
+	EncodingDeflate = "deflate"
+
+	// DefaultMaxDecompressedSize is the default maximum size in bytes of a decompressed body
+	DefaultMaxDecompressedSize = 10 * 1024 * 1024
+)
+
+const (
+	// gzipMagic0 and gzipMagic1 are the first two bytes of a gzip stream
+	gzipMagic0 = 0x1F
+	gzipMagic1 = 0x8B
+
+	// zlibDeflateMethod is the compression method of the zlib header first byte for deflate
+	zlibDeflateMethod = 0x08
+
+	// zlibDefaultCMF is the zlib header first byte for deflate with the default 32 KiB window
+	zlibDefaultCMF = 0x78
+)
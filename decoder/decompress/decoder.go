@@ -0,0 +1,226 @@
+package decompress
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+)
+
+type (
+	// Decoder wraps another decoder and decompresses the body before decoding it, it implements the Decoder
+	// interface so any decoder can opt into the decompression
+	Decoder struct {
+		decoder             gojsondecoder.Decoder
+		sniff               bool
+		maxDecompressedSize int64
+	}
+
+	// Options are the additional settings for the decoder implementation
+	Options struct {
+		// sniff indicates whether to detect the compression from the magic bytes when no content encoding is given
+		sniff bool
+
+		// maxDecompressedSize is the maximum size in bytes of the decompressed body
+		maxDecompressedSize int64
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - sniff: indicates whether to detect the compression from the magic bytes when no content encoding is given
+//   - maxDecompressedSize: the maximum size in bytes of the decompressed body, 0 uses DefaultMaxDecompressedSize and
+//     a negative value disables the limit
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	sniff bool,
+	maxDecompressedSize int64,
+) *Options {
+	return &Options{
+		sniff:               sniff,
+		maxDecompressedSize: maxDecompressedSize,
+	}
+}
+
+// NewDecoder creates a new decompressing decoder
+//
+// Parameters:
+//
+//   - decoder: the decoder used to decode the decompressed body
+//   - options: the additional settings for the decoder implementation, nil enables the sniffing and uses
+//     DefaultMaxDecompressedSize
+//
+// Returns:
+//
+//   - *Decoder: the new Decoder instance
+//   - error: the error if the decoder is nil
+func NewDecoder(
+	decoder gojsondecoder.Decoder,
+	options *Options,
+) (*Decoder, error) {
+	// Check if the decoder is nil
+	if decoder == nil {
+		return nil, gojsondecoder.ErrNilDecoder
+	}
+
+	// Initialize the options
+	if options == nil {
+		options = NewOptions(true, DefaultMaxDecompressedSize)
+	}
+
+	return &Decoder{
+		decoder:             decoder,
+		sniff:               options.sniff,
+		maxDecompressedSize: options.maxDecompressedSize,
+	}, nil
+}
+
+// Decode decompresses and decodes the body from an any value and stores it in the destination
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) Decode(
+	body any,
+	dest any,
+) error {
+	// Check the body
+	if body == nil {
+		return gojsondecoder.ErrNilBody
+	}
+
+	// Check the body type
+	reader, err := gojsondecoder.ToReader(body)
+	if err != nil {
+		return err
+	}
+	return d.DecodeReader(reader, dest)
+}
+
+// DecodeReader decompresses and decodes the body from a reader, the compression is detected from the magic bytes
+// if sniffing is enabled
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeReader(
+	reader io.Reader,
+	dest any,
+) error {
+	return d.DecodeReaderWithEncodingContext(context.Background(), reader, "", dest)
+}
+
+// DecodeReaderContext decompresses and decodes the body from a reader as DecodeReader does, stopping once the
+// context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (d Decoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
+) error {
+	return d.DecodeReaderWithEncodingContext(ctx, reader, "", dest)
+}
+
+// DecodeReaderWithEncoding decompresses the body from a reader according to its content encoding and decodes it
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - contentEncoding: The content encoding of the body, such as the Content-Encoding request header
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeReaderWithEncoding(
+	reader io.Reader,
+	contentEncoding string,
+	dest any,
+) error {
+	return d.DecodeReaderWithEncodingContext(context.Background(), reader, contentEncoding, dest)
+}
+
+// DecodeReaderWithEncodingContext decompresses the body from a reader according to its content encoding and decodes
+// it, stopping once the context is done. The wrapped decoder is called through DecodeReaderContext
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - contentEncoding: The content encoding of the body, such as the Content-Encoding request header
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (d Decoder) DecodeReaderWithEncodingContext(
+	ctx context.Context,
+	reader io.Reader,
+	contentEncoding string,
+	dest any,
+) error {
+	// Check the reader
+	if reader == nil {
+		return gojsondecoder.ErrNilReader
+	}
+
+	// Check the decoder destination
+	if dest == nil {
+		return gojsondecoder.ErrNilDestination
+	}
+
+	// Wrap the reader with the decompressing reader, the compressed body is read until the context is done
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	decompressedReader, err := NewReader(contextReader, contentEncoding, d.sniff, d.maxDecompressedSize)
+	if err != nil {
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+
+	// Decode the decompressed body
+	decodeErr := gojsondecoder.DecodeReaderContext(ctx, d.decoder, decompressedReader, dest)
+	closeErr := decompressedReader.Close()
+	return errors.Join(decodeErr, closeErr)
+}
+
+// ContentType returns the media type of the wrapped decoder, the compression is signaled by the Content-Encoding
+// header instead
+//
+// Returns:
+//
+//   - string: the media type, empty if the wrapped decoder does not define one
+func (d Decoder) ContentType() string {
+	if contentTypeDecoder, ok := d.decoder.(gojsondecoder.ContentTypeDecoder); ok {
+		return contentTypeDecoder.ContentType()
+	}
+	return ""
+}
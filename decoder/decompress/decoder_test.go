@@ -0,0 +1,200 @@
+package decompress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+)
+
+// compress compresses the data with the writer created for the buffer
+func compress(t *testing.T, data string, newWriter func(io.Writer) io.WriteCloser) []byte {
+	var buffer bytes.Buffer
+	writer := newWriter(&buffer)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestDecoderDecodeReaderWithEncoding(t *testing.T) {
+	const body = `{"name":"gopher"}`
+	gzipBody := compress(
+		t, body, func(w io.Writer) io.WriteCloser {
+			return gzip.NewWriter(w)
+		},
+	)
+	zlibBody := compress(
+		t, body, func(w io.Writer) io.WriteCloser {
+			return zlib.NewWriter(w)
+		},
+	)
+	deflateBody := compress(
+		t, body, func(w io.Writer) io.WriteCloser {
+			writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return writer
+		},
+	)
+
+	tests := []struct {
+		name            string
+		options         *Options
+		body            []byte
+		contentEncoding string
+		wantErr         bool
+		wantErrIs       error
+	}{
+		{
+			name: "sniffs the gzip bodies",
+			body: gzipBody,
+		},
+		{
+			name: "sniffs the zlib bodies",
+			body: zlibBody,
+		},
+		{
+			name: "reads the uncompressed bodies as is when sniffing",
+			body: []byte(body),
+		},
+		{
+			name:            "decompresses the gzip content encoding",
+			body:            gzipBody,
+			contentEncoding: "GZIP",
+		},
+		{
+			name:            "decompresses the legacy gzip content encoding",
+			body:            gzipBody,
+			contentEncoding: EncodingXGzip,
+		},
+		{
+			name:            "decompresses the zlib-wrapped deflate content encoding",
+			body:            zlibBody,
+			contentEncoding: EncodingDeflate,
+		},
+		{
+			name:            "decompresses the raw deflate content encoding",
+			body:            deflateBody,
+			contentEncoding: EncodingDeflate,
+		},
+		{
+			name:            "reads the identity content encoding as is",
+			body:            []byte(body),
+			contentEncoding: EncodingIdentity,
+		},
+		{
+			name:    "reads the compressed bodies as is without sniffing",
+			options: NewOptions(false, 0),
+			body:    gzipBody,
+			wantErr: true,
+		},
+		{
+			name:      "rejects the decompressed bodies exceeding the maximum size",
+			options:   NewOptions(true, int64(len(body)-1)),
+			body:      gzipBody,
+			wantErr:   true,
+			wantErrIs: ErrDecompressedTooLarge,
+		},
+		{
+			name:    "accepts the decompressed bodies of the maximum size",
+			options: NewOptions(true, int64(len(body))),
+			body:    gzipBody,
+		},
+		{
+			name:    "disables the maximum size with a negative value",
+			options: NewOptions(true, -1),
+			body:    gzipBody,
+		},
+		{
+			name:            "rejects the unknown content encodings",
+			body:            []byte(body),
+			contentEncoding: "br",
+			wantErr:         true,
+		},
+		{
+			name:            "rejects the invalid gzip headers",
+			body:            []byte(body),
+			contentEncoding: EncodingGzip,
+			wantErr:         true,
+			wantErrIs:       gzip.ErrHeader,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder, err := NewDecoder(gojsondecoderjson.NewDecoder(nil), test.options)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var dest struct {
+					Name string `json:"name"`
+				}
+				err = decoder.DecodeReaderWithEncoding(bytes.NewReader(test.body), test.contentEncoding, &dest)
+				if test.wantErr {
+					if err == nil {
+						t.Fatal("error = nil, want an error")
+					}
+					if test.wantErrIs != nil && !errors.Is(err, test.wantErrIs) {
+						t.Errorf("error = %v, want %v", err, test.wantErrIs)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				if dest.Name != "gopher" {
+					t.Errorf("name = %q, want %q", dest.Name, "gopher")
+				}
+			},
+		)
+	}
+}
+
+func TestNewReaderSniffing(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "does not mistake the JSON numbers that are valid zlib headers for zlib bodies",
+			body: "80",
+		},
+		{
+			name: "reads the bodies shorter than the magic bytes",
+			body: "1",
+		},
+		{
+			name: "reads the empty bodies",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				reader, err := NewReader(strings.NewReader(test.body), "", true, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer reader.Close()
+
+				got, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != test.body {
+					t.Errorf("read = %q, want %q", got, test.body)
+				}
+			},
+		)
+	}
+}
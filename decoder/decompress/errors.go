@@ -0,0 +1,14 @@
+package decompress
+
+import (
+	"errors"
+)
+
+const (
+	ErrUnsupportedEncoding = "%w: %s"
+)
+
+var (
+	ErrDecompressedTooLarge   = errors.New("decompressed body exceeds the maximum size")
+	ErrUnknownContentEncoding = errors.New("unsupported content encoding")
+)
@@ -0,0 +1,168 @@
+package decompress
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// readCloser is the decompressing reader, it limits the decompressed size and closes the decompressor
+	readCloser struct {
+		reader    io.Reader
+		closer    io.Closer
+		remaining int64
+		limited   bool
+	}
+)
+
+// Read reads the decompressed bytes, failing once the maximum decompressed size is exceeded
+//
+// Parameters:
+//
+//   - p: the buffer to read into
+//
+// Returns:
+//
+//   - int: the number of bytes read
+//   - error: the error if any
+func (r *readCloser) Read(p []byte) (int, error) {
+	if !r.limited {
+		return r.reader.Read(p)
+	}
+
+	// Read at most one byte more than allowed, so an exceeded limit is detected
+	if r.remaining < 0 {
+		return 0, ErrDecompressedTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n + int(r.remaining), ErrDecompressedTooLarge
+	}
+	return n, err
+}
+
+// Close closes the decompressor
+//
+// Returns:
+//
+//   - error: the error if any
+func (r *readCloser) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// NewReader returns a reader that decompresses the body according to its content encoding
+//
+// Parameters:
+//
+//   - reader: the reader of the body
+//   - contentEncoding: the content encoding of the body, an empty value sniffs the gzip and zlib magic bytes if
+//     sniff is true, otherwise the body is read as is
+//   - sniff: whether to detect the compression from the magic bytes when no content encoding is given
+//   - maxDecompressedSize: the maximum size in bytes of the decompressed body, 0 uses DefaultMaxDecompressedSize
+//     and a negative value disables the limit
+//
+// Returns:
+//
+//   - io.ReadCloser: the decompressing reader, it must be closed once read
+//   - error: the error if the content encoding is not supported or the compressed header is invalid
+func NewReader(
+	reader io.Reader,
+	contentEncoding string,
+	sniff bool,
+	maxDecompressedSize int64,
+) (io.ReadCloser, error) {
+	// Initialize the maximum decompressed size
+	if maxDecompressedSize == 0 {
+		maxDecompressedSize = DefaultMaxDecompressedSize
+	}
+
+	// Buffer the reader so the magic bytes can be peeked
+	bufferedReader := bufio.NewReader(reader)
+
+	// Select the decompressor
+	var decompressor io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "":
+		if sniff && isGzip(bufferedReader) {
+			decompressor, err = gzip.NewReader(bufferedReader)
+		} else if sniff && isZlib(bufferedReader, true) {
+			decompressor, err = zlib.NewReader(bufferedReader)
+		}
+	case EncodingIdentity:
+	case EncodingGzip, EncodingXGzip:
+		decompressor, err = gzip.NewReader(bufferedReader)
+	case EncodingDeflate:
+		// Clients send both the zlib-wrapped format defined by HTTP and raw deflate
+		if isZlib(bufferedReader, false) {
+			decompressor, err = zlib.NewReader(bufferedReader)
+		} else {
+			decompressor = flate.NewReader(bufferedReader)
+		}
+	default:
+		return nil, fmt.Errorf(ErrUnsupportedEncoding, ErrUnknownContentEncoding, contentEncoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the decompressor, or the buffered reader if the body is not compressed
+	wrapped := &readCloser{
+		reader:    bufferedReader,
+		remaining: maxDecompressedSize,
+		limited:   maxDecompressedSize > 0,
+	}
+	if decompressor != nil {
+		wrapped.reader = decompressor
+		wrapped.closer = decompressor
+	}
+	return wrapped, nil
+}
+
+// isGzip checks whether the buffered reader starts with the gzip magic bytes
+//
+// Parameters:
+//
+//   - reader: the buffered reader
+//
+// Returns:
+//
+//   - bool: true if the body is gzip compressed
+func isGzip(reader *bufio.Reader) bool {
+	header, err := reader.Peek(2)
+	return err == nil && header[0] == gzipMagic0 && header[1] == gzipMagic1
+}
+
+// isZlib checks whether the buffered reader starts with a valid zlib header using deflate
+//
+// Parameters:
+//
+//   - reader: the buffered reader
+//   - sniffing: whether the content encoding is unknown, if so only the default 32 KiB window is accepted, since
+//     other valid headers can also be the start of a JSON number
+//
+// Returns:
+//
+//   - bool: true if the body is zlib compressed
+func isZlib(reader *bufio.Reader, sniffing bool) bool {
+	header, err := reader.Peek(2)
+	if err != nil {
+		return false
+	}
+	if sniffing && header[0] != zlibDefaultCMF {
+		return false
+	}
+	return header[0]&0x0F == zlibDeflateMethod && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
@@ -2,12 +2,228 @@ package decoder
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	ErrLimitExceeded = "exceeded the maximum %s of %d"
+	ErrTypedCause    = "%w: %w"
 )
 
 var (
-	ErrInvalidInstance = errors.New("invalid instance provided to create a reader")
-	ErrNilBody         = errors.New("body cannot be nil")
-	ErrNilReader       = errors.New("reader cannot be nil")
-	ErrNilDestination  = errors.New("destination cannot be nil")
-	ErrNilDecoder      = errors.New("decoder is nil")
+	ErrInvalidInstance            = errors.New("invalid instance provided to create a reader")
+	ErrNilBody                    = errors.New("body cannot be nil")
+	ErrNilReader                  = errors.New("reader cannot be nil")
+	ErrNilDestination             = errors.New("destination cannot be nil")
+	ErrNilDecoder                 = errors.New("decoder is nil")
+	ErrDestinationNotSlicePointer = errors.New("destination must be a pointer to a slice")
+	ErrUnknownField               = errors.New("unknown field")
+	ErrInvalidSyntax              = errors.New("invalid JSON syntax")
+	ErrInvalidValue               = errors.New("invalid value")
+	ErrValidation                 = errors.New("validation failed")
+	ErrDuplicateKey               = errors.New("duplicate object key")
+	ErrTrailingData               = errors.New("unexpected data after the top-level value")
+	ErrInvalidUTF8                = errors.New("invalid UTF-8 sequence")
+	ErrRecordTooLarge             = errors.New("record exceeds the maximum record size")
 )
+
+type (
+	// DecodeError is the error returned when a document cannot be decoded, it carries the location of the problem
+	// in the document
+	DecodeError struct {
+		// Path is the RFC 6901 JSON Pointer to the value that cannot be decoded, empty for the whole document
+		Path string
+
+		// Offset is the byte offset where the problem was detected, -1 if it is not known
+		Offset int64
+
+		// Line is the line where the problem was detected, starting at 1, 0 if it is not known
+		Line int
+
+		// Column is the byte column where the problem was detected, starting at 1, 0 if it is not known
+		Column int
+
+		// Expected is the Go type the value was decoded into, nil if it is not known
+		Expected reflect.Type
+
+		// Err is the wrapped cause
+		Err error
+	}
+
+	// DecodeErrors is the error returned by the decoders in the collect mode, it holds every error found in the
+	// document ordered by their offset. It unwraps into its entries, so errors.Is and errors.As match any of them
+	DecodeErrors []*DecodeError
+
+	// LimitError is the error returned when a document exceeds a resource limit
+	LimitError struct {
+		// Limit is the exceeded limit
+		Limit LimitKind
+
+		// Max is the maximum allowed by the limit
+		Max int64
+	}
+)
+
+// NewDecodeError creates a new DecodeError instance
+//
+// Parameters:
+//
+//   - path: the RFC 6901 JSON Pointer to the value that cannot be decoded
+//   - position: the position where the problem was detected
+//   - expected: the Go type the value was decoded into, nil if it is not known
+//   - err: the wrapped cause
+//
+// Returns:
+//
+//   - *DecodeError: the new DecodeError instance
+func NewDecodeError(
+	path string,
+	position Position,
+	expected reflect.Type,
+	err error,
+) *DecodeError {
+	return &DecodeError{
+		Path:     path,
+		Offset:   position.Offset,
+		Line:     position.Line,
+		Column:   position.Column,
+		Expected: expected,
+		Err:      err,
+	}
+}
+
+// Position returns the position where the problem was detected
+//
+// Returns:
+//
+//   - Position: the position
+func (d DecodeError) Position() Position {
+	return Position{
+		Offset: d.Offset,
+		Line:   d.Line,
+		Column: d.Column,
+	}
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: the error message
+func (d DecodeError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("failed to decode ")
+	if d.Path != "" {
+		builder.WriteString(fmt.Sprintf("%q", d.Path))
+	} else {
+		builder.WriteString("document")
+	}
+	if d.Line > 0 {
+		builder.WriteString(fmt.Sprintf(" at line %d, column %d", d.Line, d.Column))
+	} else if d.Offset >= 0 {
+		builder.WriteString(fmt.Sprintf(" at offset %d", d.Offset))
+	}
+	if d.Expected != nil {
+		builder.WriteString(fmt.Sprintf(" into %s", d.Expected))
+	}
+	builder.WriteString(": ")
+	builder.WriteString(d.Err.Error())
+	return builder.String()
+}
+
+// Unwrap returns the wrapped cause
+//
+// Returns:
+//
+//   - error: the wrapped cause
+func (d DecodeError) Unwrap() error {
+	return d.Err
+}
+
+// NewDecodeErrors joins the errors into a DecodeErrors, flattening the nested DecodeErrors and joined errors, and
+// wrapping the errors that are not a *DecodeError without a location
+//
+// Parameters:
+//
+//   - errs: the errors to join, the nil ones are ignored
+//
+// Returns:
+//
+//   - error: the DecodeErrors, nil if there are no errors
+func NewDecodeErrors(errs ...error) error {
+	var decodeErrs DecodeErrors
+	for _, err := range errs {
+		switch typedErr := err.(type) {
+		case nil:
+			continue
+		case DecodeErrors:
+			decodeErrs = append(decodeErrs, typedErr...)
+		case *DecodeError:
+			decodeErrs = append(decodeErrs, typedErr)
+		case interface{ Unwrap() []error }:
+			if joinedErrs, ok := NewDecodeErrors(typedErr.Unwrap()...).(DecodeErrors); ok {
+				decodeErrs = append(decodeErrs, joinedErrs...)
+			}
+		default:
+			decodeErrs = append(decodeErrs, NewDecodeError("", UnknownPosition, nil, err))
+		}
+	}
+	if len(decodeErrs) == 0 {
+		return nil
+	}
+	return decodeErrs
+}
+
+// Error returns the error messages, one per line
+//
+// Returns:
+//
+//   - string: the error messages
+func (d DecodeErrors) Error() string {
+	messages := make([]string, len(d))
+	for i, decodeErr := range d {
+		messages[i] = decodeErr.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the entries
+//
+// Returns:
+//
+//   - []error: the entries
+func (d DecodeErrors) Unwrap() []error {
+	errs := make([]error, len(d))
+	for i, decodeErr := range d {
+		errs[i] = decodeErr
+	}
+	return errs
+}
+
+// NewLimitError creates a new LimitError instance
+//
+// Parameters:
+//
+//   - limit: the exceeded limit
+//   - maximum: the maximum allowed by the limit
+//
+// Returns:
+//
+//   - *LimitError: the new LimitError instance
+func NewLimitError(limit LimitKind, maximum int64) *LimitError {
+	return &LimitError{
+		Limit: limit,
+		Max:   maximum,
+	}
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: the error message
+func (l LimitError) Error() string {
+	return fmt.Sprintf(ErrLimitExceeded, l.Limit, l.Max)
+}
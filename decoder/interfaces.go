@@ -1,6 +1,7 @@
 package decoder
 
 import (
+	"context"
 	"io"
 )
 
@@ -16,4 +17,27 @@ type (
 			dest any,
 		) error
 	}
+
+	// Validator is the interface implemented by the destinations that validate themselves after being decoded in
+	// the collect mode, the returned *DecodeError or DecodeErrors keep their paths
+	Validator interface {
+		Validate() error
+	}
+
+	// ContextDecoder is the interface implemented by the decoders that stop reading and decoding once the context
+	// is done
+	ContextDecoder interface {
+		Decoder
+		DecodeReaderContext(
+			ctx context.Context,
+			reader io.Reader,
+			dest any,
+		) error
+	}
+
+	// ContentTypeDecoder is the interface implemented by the decoders whose input is not application/json
+	ContentTypeDecoder interface {
+		Decoder
+		ContentType() string
+	}
 )
@@ -0,0 +1,478 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+)
+
+type (
+	// ArrayDecoder is the decoder that streams a top-level JSON array, each element is read and decoded
+	// independently with the element decoder, without reading the whole array first
+	ArrayDecoder struct {
+		elementDecoder gojsondecoder.Decoder
+		maxElementSize int64
+		maxElements    int
+	}
+
+	// ArrayOptions are the additional settings for the array decoder implementation
+	ArrayOptions struct {
+		// elementDecoder is the decoder used to decode each element
+		elementDecoder gojsondecoder.Decoder
+
+		// maxElementSize is the maximum size in bytes of an element
+		maxElementSize int64
+
+		// maxElements is the maximum number of elements of the array
+		maxElements int
+	}
+
+	// elementReader is the reader that bounds the bytes read for the element being decoded, so an oversized element
+	// is rejected before it is buffered
+	elementReader struct {
+		reader io.Reader
+		read   int64
+		limit  int64
+	}
+)
+
+// NewArrayOptions creates a new ArrayOptions instance
+//
+// Parameters:
+//
+//   - elementDecoder: the decoder used to decode each element, nil uses the plain JSON decoder. The protojson
+//     NewArrayDecoder sets the protojson decoder as the element decoder for the elements with proto.Message fields
+//   - maxElementSize: the maximum size in bytes of an element, checked while the element is read, 0 means no limit
+//   - maxElements: the maximum number of elements of the array, 0 means no limit
+//
+// Returns:
+//
+//   - *ArrayOptions: the new ArrayOptions instance
+func NewArrayOptions(
+	elementDecoder gojsondecoder.Decoder,
+	maxElementSize int64,
+	maxElements int,
+) *ArrayOptions {
+	return &ArrayOptions{
+		elementDecoder: elementDecoder,
+		maxElementSize: maxElementSize,
+		maxElements:    maxElements,
+	}
+}
+
+// NewArrayDecoder creates a new JSON array decoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the array decoder implementation
+//
+// Returns:
+//
+//   - *ArrayDecoder: the new ArrayDecoder instance
+func NewArrayDecoder(options *ArrayOptions) *ArrayDecoder {
+	// Initialize the settings
+	var elementDecoder gojsondecoder.Decoder
+	var maxElementSize int64
+	maxElements := 0
+	if options != nil {
+		elementDecoder = options.elementDecoder
+		maxElementSize = options.maxElementSize
+		maxElements = options.maxElements
+	}
+
+	// Initialize the element decoder
+	if elementDecoder == nil {
+		elementDecoder = NewDecoder(nil)
+	}
+
+	return &ArrayDecoder{
+		elementDecoder: elementDecoder,
+		maxElementSize: maxElementSize,
+		maxElements:    maxElements,
+	}
+}
+
+// Decode decodes the JSON array from an any value and appends each element to the destination slice
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The pointer to the slice to append the decoded elements to
+//
+// Returns:
+//
+//   - error: The error if any
+func (a ArrayDecoder) Decode(
+	body any,
+	dest any,
+) error {
+	// Check the body
+	if body == nil {
+		return gojsondecoder.ErrNilBody
+	}
+
+	// Check the body type
+	reader, err := gojsondecoder.ToReader(body)
+	if err != nil {
+		return err
+	}
+	return a.DecodeReader(reader, dest)
+}
+
+// DecodeReader decodes the JSON array from a reader and appends each element to the destination slice
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded elements to
+//
+// Returns:
+//
+//   - error: The error if any
+func (a ArrayDecoder) DecodeReader(
+	reader io.Reader,
+	dest any,
+) error {
+	return a.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes the JSON array from a reader and appends each element to the destination slice,
+// stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded elements to
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (a ArrayDecoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
+) error {
+	// Check the reader
+	if reader == nil {
+		return gojsondecoder.ErrNilReader
+	}
+
+	// Check the decoder destination
+	if dest == nil {
+		return gojsondecoder.ErrNilDestination
+	}
+
+	// Check the destination is a pointer to a slice
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return gojsondecoder.ErrDestinationNotSlicePointer
+	}
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	// Decode each element into a new value
+	return a.scanElements(ctx, reader, func(index int, offset int64, element json.RawMessage) error {
+		elemValue := reflect.New(elemType)
+		if err := gojsondecoder.DecodeIntoValue(a.elementDecoder, []byte(element), elemValue); err != nil {
+			return newElementError(index, offset, element, err)
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elemValue.Elem()))
+		return nil
+	})
+}
+
+// scanElements reads the top-level JSON array token by token and calls the function with each raw element, until
+// the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the array from
+//   - fn: The function to call with the element index, the offset of the element and the raw element, a returned
+//     error stops the scan and is returned
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (a ArrayDecoder) scanElements(
+	ctx context.Context,
+	reader io.Reader,
+	fn func(index int, offset int64, element json.RawMessage) error,
+) error {
+	// Keep the read errors of the context reader, which has to wrap the given reader so its blocked reads can be
+	// interrupted
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	readErrReader := &readErrorReader{reader: contextReader}
+	elementReader := &elementReader{reader: readErrReader}
+	jsonDecoder := json.NewDecoder(elementReader)
+
+	// Read the opening bracket
+	token, err := jsonDecoder.Token()
+	if err != nil {
+		return toScanError(ctx, readErrReader, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return gojsondecoder.NewDecodeError(
+			"",
+			gojsondecoder.Position{Offset: jsonDecoder.InputOffset()},
+			nil,
+			ErrExpectedArrayStart,
+		)
+	}
+
+	// Read each element
+	index := 0
+	for jsonDecoder.More() {
+		// Check the context
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+
+		// Check the number of elements
+		offset := jsonDecoder.InputOffset() + leadingSeparatorSize(jsonDecoder.Buffered())
+		if a.maxElements > 0 && index >= a.maxElements {
+			return newElementLimitError(index, offset, gojsondecoder.LimitElements, int64(a.maxElements))
+		}
+
+		// Read the raw element, reading at most one byte more than allowed, as the end of a number is only known
+		// once the next byte is read
+		if a.maxElementSize > 0 {
+			elementReader.limit = offset + a.maxElementSize + 1
+		}
+		var element json.RawMessage
+		decodeErr := jsonDecoder.Decode(&element)
+		elementReader.limit = 0
+		if errors.Is(decodeErr, errElementTooLarge) {
+			return newElementLimitError(index, offset, gojsondecoder.LimitElementSize, a.maxElementSize)
+		}
+		if decodeErr != nil {
+			return toScanError(ctx, readErrReader, decodeErr)
+		}
+
+		// Check the size of the element, as it may be complete without reading past the limit
+		if a.maxElementSize > 0 && int64(len(element)) > a.maxElementSize {
+			return newElementLimitError(index, offset, gojsondecoder.LimitElementSize, a.maxElementSize)
+		}
+
+		if fnErr := fn(index, offset, element); fnErr != nil {
+			return fnErr
+		}
+		index++
+	}
+
+	// Read the closing bracket
+	token, err = jsonDecoder.Token()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return toScanError(ctx, readErrReader, err)
+	}
+	if delim, ok := token.(json.Delim); err != nil || !ok || delim != ']' {
+		return gojsondecoder.NewDecodeError(
+			"",
+			gojsondecoder.Position{Offset: jsonDecoder.InputOffset()},
+			nil,
+			ErrExpectedArrayEnd,
+		)
+	}
+	return nil
+}
+
+// toScanError converts the error returned while reading the array into a *DecodeError, or returns the cause of the
+// context if it is done, as the read was interrupted, and the read errors as is
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - readErrReader: The reader that keeps the read error
+//   - err: The error returned while reading the array
+//
+// Returns:
+//
+//   - error: The error
+func toScanError(ctx context.Context, readErrReader *readErrorReader, err error) error {
+	if ctxErr := context.Cause(ctx); ctxErr != nil {
+		return ctxErr
+	}
+	if readErrReader.isReadError(err) {
+		return err
+	}
+	return gojsondecoder.ToDecodeError(nil, err)
+}
+
+// Read reads the array, failing with errElementTooLarge once the limit of the element being decoded is reached
+//
+// Parameters:
+//
+//   - p: the buffer to read into
+//
+// Returns:
+//
+//   - int: the number of bytes read
+//   - error: the error if any
+func (e *elementReader) Read(p []byte) (int, error) {
+	if e.limit > 0 {
+		remaining := e.limit - e.read
+		if remaining <= 0 {
+			return 0, errElementTooLarge
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := e.reader.Read(p)
+	e.read += int64(n)
+	return n, err
+}
+
+// leadingSeparatorSize returns the number of whitespace and comma bytes buffered before the next element
+//
+// Parameters:
+//
+//   - buffered: The reader of the bytes buffered by the JSON decoder
+//
+// Returns:
+//
+//   - int64: The number of bytes
+func leadingSeparatorSize(buffered io.Reader) int64 {
+	var chunk [64]byte
+	var size int64
+	for {
+		n, err := buffered.Read(chunk[:])
+		for _, c := range chunk[:n] {
+			switch c {
+			case ' ', '\t', '\r', '\n', ',':
+				size++
+			default:
+				return size
+			}
+		}
+		if err != nil || n == 0 {
+			return size
+		}
+	}
+}
+
+// newElementLimitError creates the error returned when the array exceeds a limit, located at the element
+//
+// Parameters:
+//
+//   - index: The index of the element
+//   - offset: The offset of the element in the array
+//   - limit: The exceeded limit
+//   - maximum: The maximum allowed by the limit
+//
+// Returns:
+//
+//   - error: The *DecodeError wrapping the *LimitError
+func newElementLimitError(index int, offset int64, limit gojsondecoder.LimitKind, maximum int64) error {
+	return gojsondecoder.NewDecodeError(
+		gojsondecoder.AppendPointerToken("", strconv.Itoa(index)),
+		gojsondecoder.Position{Offset: offset},
+		nil,
+		gojsondecoder.NewLimitError(limit, maximum),
+	)
+}
+
+// newElementError creates the error returned when an element cannot be decoded, located in the array
+//
+// Parameters:
+//
+//   - index: The index of the element
+//   - offset: The offset of the element in the array
+//   - element: The raw element
+//   - err: The error returned while decoding the element
+//
+// Returns:
+//
+//   - error: The error
+func newElementError(index int, offset int64, element json.RawMessage, err error) error {
+	return fmt.Errorf(
+		ErrDecodeArrayElement,
+		index,
+		gojsondecoder.NestDecodeError(element, err, strconv.Itoa(index), gojsondecoder.Position{Offset: offset}),
+	)
+}
+
+// DecodeArraySeq returns a sequence that decodes the top-level JSON array element by element, as they are read
+//
+// An element that cannot be decoded is yielded with its error and the sequence continues with the next element,
+// unless the caller breaks the loop. Syntax errors and exceeded limits are yielded last
+//
+// Parameters:
+//
+//   - decoder: The JSON array decoder, nil uses the default decoder
+//   - reader: The reader to read the array from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded elements and errors
+func DecodeArraySeq[T any](
+	decoder *ArrayDecoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return DecodeArraySeqContext[T](context.Background(), decoder, reader)
+}
+
+// DecodeArraySeqContext returns a sequence that decodes the top-level JSON array element by element, as they are
+// read, until the context is done. The cause of the context is yielded last once it is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - decoder: The JSON array decoder, nil uses the default decoder
+//   - reader: The reader to read the array from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded elements and errors
+func DecodeArraySeqContext[T any](
+	ctx context.Context,
+	decoder *ArrayDecoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// Check the reader
+		if reader == nil {
+			yield(zero, gojsondecoder.ErrNilReader)
+			return
+		}
+
+		// Initialize the decoder
+		if decoder == nil {
+			decoder = NewArrayDecoder(nil)
+		}
+
+		// Decode each element, errStopped is returned when the caller breaks the loop
+		err := decoder.scanElements(ctx, reader, func(index int, offset int64, element json.RawMessage) error {
+			var value T
+			if decodeErr := gojsondecoder.DecodeIntoValue(
+				decoder.elementDecoder,
+				[]byte(element),
+				reflect.ValueOf(&value),
+			); decodeErr != nil {
+				if !yield(zero, newElementError(index, offset, element, decodeErr)) {
+					return errStopped
+				}
+				return nil
+			}
+			if !yield(value, nil) {
+				return errStopped
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopped) {
+			yield(zero, err)
+		}
+	}
+}
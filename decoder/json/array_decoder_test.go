@@ -0,0 +1,185 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+// countingReader counts the bytes read from the reader
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+func TestArrayDecoderDecodeReader(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		maxElementSize int64
+		maxElements    int
+		want           []int
+		wantLimit      gojsondecoder.LimitKind
+		wantPath       string
+		wantOffset     int64
+		wantMaxRead    int64
+	}{
+		{
+			name:  "decodes every element",
+			input: "[1, 2, 3]",
+			want:  []int{1, 2, 3},
+		},
+		{
+			name:           "accepts the elements of the maximum size",
+			input:          "[ 123 ,\n 456 ]",
+			maxElementSize: 3,
+			want:           []int{123, 456},
+		},
+		{
+			name:        "rejects the elements over the maximum number",
+			input:       "[1, 2, 3]",
+			maxElements: 2,
+			want:        []int{1, 2},
+			wantLimit:   gojsondecoder.LimitElements,
+			wantPath:    "/2",
+			wantOffset:  7,
+		},
+		{
+			name:           "rejects the oversized numbers",
+			input:          "[1, 12345]",
+			maxElementSize: 4,
+			want:           []int{1},
+			wantLimit:      gojsondecoder.LimitElementSize,
+			wantPath:       "/1",
+			wantOffset:     4,
+		},
+		{
+			name:           "rejects the oversized strings",
+			input:          "[1, \"12345\"]",
+			maxElementSize: 6,
+			want:           []int{1},
+			wantLimit:      gojsondecoder.LimitElementSize,
+			wantPath:       "/1",
+			wantOffset:     4,
+		},
+		{
+			name:           "stops reading an oversized element",
+			input:          "[1, \"" + strings.Repeat("x", 1<<20) + "\"]",
+			maxElementSize: 16,
+			want:           []int{1},
+			wantLimit:      gojsondecoder.LimitElementSize,
+			wantPath:       "/1",
+			wantOffset:     4,
+			wantMaxRead:    512,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewArrayDecoder(NewArrayOptions(nil, test.maxElementSize, test.maxElements))
+				reader := &countingReader{reader: strings.NewReader(test.input)}
+				var got []int
+				err := decoder.DecodeReader(reader, &got)
+
+				// Check the decoded elements
+				if !slices.Equal(got, test.want) {
+					t.Errorf("decoded elements = %v, want %v", got, test.want)
+				}
+
+				// Check the bytes read, the JSON decoder reads ahead in chunks of up to 512 bytes
+				if test.wantMaxRead > 0 && reader.read > test.wantMaxRead {
+					t.Errorf("read %d bytes, want at most %d", reader.read, test.wantMaxRead)
+				}
+
+				// Check the exceeded limit
+				if test.wantPath == "" {
+					if err != nil {
+						t.Fatalf("error = %v, want nil", err)
+					}
+					return
+				}
+				var limitErr *gojsondecoder.LimitError
+				var decodeErr *gojsondecoder.DecodeError
+				if !errors.As(err, &limitErr) || !errors.As(err, &decodeErr) {
+					t.Fatalf("error = %v, want a *DecodeError wrapping a *LimitError", err)
+				}
+				if limitErr.Limit != test.wantLimit {
+					t.Errorf("limit = %v, want %v", limitErr.Limit, test.wantLimit)
+				}
+				if decodeErr.Path != test.wantPath || decodeErr.Offset != test.wantOffset {
+					t.Errorf(
+						"location = %q at %d, want %q at %d",
+						decodeErr.Path,
+						decodeErr.Offset,
+						test.wantPath,
+						test.wantOffset,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestDecodeArraySeq(t *testing.T) {
+	input := "[{\"id\":1}, {\"id\":\"x\"}, {\"id\":3}]"
+	var got []int
+	var errs []error
+	for value, err := range DecodeArraySeq[struct {
+		ID int `json:"id"`
+	}](nil, strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, value.ID)
+	}
+	if !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("elements = %v, want [1 3]", got)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want 1 error", errs)
+	}
+
+	// The error is located in the array
+	var decodeErr *gojsondecoder.DecodeError
+	if !errors.As(errs[0], &decodeErr) || decodeErr.Path != "/1/id" {
+		t.Errorf("error = %v, want a *DecodeError at /1/id", errs[0])
+	}
+}
+
+func TestDecodeArraySeqContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the context once the second element is decoded, while the rest of the array is not sent yet
+	var got []int
+	var gotErr error
+	for element, err := range DecodeArraySeqContext[int](ctx, nil, newBlockedReader(t, "[1,2,")) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, element)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("elements = %v, want %v", got, []int{1, 2})
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("error = %v, want %v", gotErr, context.Canceled)
+	}
+}
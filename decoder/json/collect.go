@@ -0,0 +1,422 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+var (
+	// unmarshalerType is the reflect type of the json.Unmarshaler interface
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+	// textUnmarshalerType is the reflect type of the encoding.TextUnmarshaler interface
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// UnmarshalAll decodes the data into the destination like json.Unmarshal, but instead of stopping at the first
+// error it keeps decoding the valid members, so every type mismatch and unknown field is reported
+//
+// Parameters:
+//
+//   - data: The JSON data to decode
+//   - dest: The pointer to the destination
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//
+// Returns:
+//
+//   - error: The DecodeErrors ordered by offset and located in the data, the *DecodeError if the data is not valid
+//     JSON, nil if there are no errors
+func UnmarshalAll(
+	data []byte,
+	dest any,
+	disallowUnknownFields bool,
+) error {
+	// Check the data is valid JSON and the destination is a non-nil pointer, as json.Unmarshal does, the errors
+	// cannot be collected otherwise
+	destValue := reflect.ValueOf(dest)
+	if !json.Valid(data) || destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return gojsondecoder.ToDecodeError(data, Unmarshal(data, dest, disallowUnknownFields))
+	}
+
+	// Collect the errors and locate them in the data
+	var errs []error
+	collectErrors(data, destValue.Elem(), "", disallowUnknownFields, &errs)
+	return gojsondecoder.ToDecodeError(data, gojsondecoder.NewDecodeErrors(errs...))
+}
+
+// Unmarshal decodes the data into the destination like json.Unmarshal, optionally rejecting the object members
+// that do not match a struct field
+//
+// Parameters:
+//
+//   - data: The JSON data to decode
+//   - dest: The pointer to the destination
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are an error
+//
+// Returns:
+//
+//   - error: The error if any
+func Unmarshal(data []byte, dest any, disallowUnknownFields bool) error {
+	if !disallowUnknownFields {
+		return json.Unmarshal(data, dest)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		return err
+	}
+
+	// Reject the data after the value, as json.Unmarshal does
+	offset := decoder.InputOffset()
+	if trailing := bytes.TrimLeft(data[offset:], " \t\r\n"); len(trailing) > 0 {
+		return gojsondecoder.NewDecodeError(
+			"",
+			gojsondecoder.Position{Offset: int64(len(data) - len(trailing))},
+			nil,
+			gojsondecoder.ErrTrailingData,
+		)
+	}
+	return nil
+}
+
+// collectErrors decodes the data into the value, and if it fails, decodes each object member or array element on
+// its own to collect the errors of all of them
+//
+// Parameters:
+//
+//   - data: The JSON data of the value
+//   - value: The addressable value to decode the data into
+//   - path: The JSON Pointer to the value
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//   - errs: The collected errors
+func collectErrors(
+	data []byte,
+	value reflect.Value,
+	path string,
+	disallowUnknownFields bool,
+	errs *[]error,
+) {
+	// Decode the value as a whole
+	err := Unmarshal(data, value.Addr().Interface(), disallowUnknownFields)
+	if err == nil {
+		return
+	}
+
+	// Syntax errors and custom unmarshalers cannot be descended into
+	var typeErr *json.UnmarshalTypeError
+	isUnknownFieldErr := errors.Is(gojsondecoder.ToDecodeError(nil, err), gojsondecoder.ErrUnknownField)
+	if (!errors.As(err, &typeErr) && !isUnknownFieldErr) || hasCustomUnmarshaler(value) {
+		*errs = append(*errs, gojsondecoder.NewDecodeError(path, gojsondecoder.UnknownPosition, value.Type(), err))
+		return
+	}
+
+	// Descend into the value to collect the errors of its members
+	var descended bool
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		collectErrors(data, value.Elem(), path, disallowUnknownFields, errs)
+		return
+	case reflect.Struct:
+		descended = collectStructErrors(data, value, path, disallowUnknownFields, errs)
+	case reflect.Slice, reflect.Array:
+		descended = collectArrayErrors(data, value, path, disallowUnknownFields, errs)
+	case reflect.Map:
+		descended = collectMapErrors(data, value, path, disallowUnknownFields, errs)
+	}
+	if !descended {
+		*errs = append(*errs, gojsondecoder.NewDecodeError(path, gojsondecoder.UnknownPosition, value.Type(), err))
+	}
+}
+
+// collectStructErrors decodes each member of the JSON object into the matching struct field
+//
+// Parameters:
+//
+//   - data: The JSON data of the struct
+//   - value: The addressable struct value
+//   - path: The JSON Pointer to the struct
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//   - errs: The collected errors
+//
+// Returns:
+//
+//   - bool: Whether the data is a JSON object that could be descended into
+func collectStructErrors(
+	data []byte,
+	value reflect.Value,
+	path string,
+	disallowUnknownFields bool,
+	errs *[]error,
+) bool {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return false
+	}
+
+	fields := jsonFields(value.Type())
+	for _, name := range sortedKeys(members) {
+		memberPath := gojsondecoder.AppendPointerToken(path, name)
+
+		// Find the struct field
+		index, ok := findField(fields, name)
+		if !ok {
+			if disallowUnknownFields {
+				*errs = append(
+					*errs,
+					gojsondecoder.NewDecodeError(
+						memberPath,
+						gojsondecoder.UnknownPosition,
+						nil,
+						gojsondecoder.ErrUnknownField,
+					),
+				)
+			}
+			continue
+		}
+
+		// Get the field, allocating the embedded struct pointers
+		fieldValue, fieldErr := fieldByIndex(value, index)
+		if fieldErr != nil {
+			continue
+		}
+		collectErrors(members[name], fieldValue, memberPath, disallowUnknownFields, errs)
+	}
+	return true
+}
+
+// collectArrayErrors decodes each element of the JSON array into the matching slice or array element
+//
+// Parameters:
+//
+//   - data: The JSON data of the array
+//   - value: The addressable slice or array value
+//   - path: The JSON Pointer to the array
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//   - errs: The collected errors
+//
+// Returns:
+//
+//   - bool: Whether the data is a JSON array that could be descended into
+func collectArrayErrors(
+	data []byte,
+	value reflect.Value,
+	path string,
+	disallowUnknownFields bool,
+	errs *[]error,
+) bool {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return false
+	}
+
+	// Resize the slice to hold every element
+	if value.Kind() == reflect.Slice && value.Len() != len(elements) {
+		value.Set(reflect.MakeSlice(value.Type(), len(elements), len(elements)))
+	}
+
+	for i := 0; i < len(elements) && i < value.Len(); i++ {
+		elementPath := gojsondecoder.AppendPointerToken(path, strconv.Itoa(i))
+		collectErrors(elements[i], value.Index(i), elementPath, disallowUnknownFields, errs)
+	}
+	return true
+}
+
+// collectMapErrors decodes each member of the JSON object into a new map entry
+//
+// Parameters:
+//
+//   - data: The JSON data of the map
+//   - value: The addressable map value
+//   - path: The JSON Pointer to the map
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//   - errs: The collected errors
+//
+// Returns:
+//
+//   - bool: Whether the data is a JSON object that could be descended into
+func collectMapErrors(
+	data []byte,
+	value reflect.Value,
+	path string,
+	disallowUnknownFields bool,
+	errs *[]error,
+) bool {
+	// Only the string keys can be descended into, as the other keys need to be parsed
+	mapType := value.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return false
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return false
+	}
+
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(mapType, len(members)))
+	}
+	for _, key := range sortedKeys(members) {
+		elemValue := reflect.New(mapType.Elem()).Elem()
+		memberPath := gojsondecoder.AppendPointerToken(path, key)
+		collectErrors(members[key], elemValue, memberPath, disallowUnknownFields, errs)
+		value.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), elemValue)
+	}
+	return true
+}
+
+// jsonFields returns the index of the struct fields by their JSON name, including the promoted fields of the
+// embedded structs, following the encoding/json naming rules
+//
+// Parameters:
+//
+//   - structType: The struct type
+//
+// Returns:
+//
+//   - map[string][]int: The field indexes by JSON name
+func jsonFields(structType reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	depths := make(map[string]int)
+	promotingFields := make(map[string]struct{})
+	for _, structField := range reflect.VisibleFields(structType) {
+		// Only the fields of the untagged embedded structs are promoted
+		index := structField.Index
+		if len(index) > 1 {
+			if _, ok := promotingFields[fmt.Sprint(index[:len(index)-1])]; !ok {
+				continue
+			}
+		}
+
+		tag := structField.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Skip the untagged embedded structs, whose fields are promoted, and the unexported fields
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if structField.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			promotingFields[fmt.Sprint(index)] = struct{}{}
+			continue
+		}
+		if !structField.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+
+		// The shallower fields hide the deeper ones
+		depth := len(index)
+		if previousDepth, ok := depths[name]; ok && previousDepth <= depth {
+			continue
+		}
+		fields[name] = index
+		depths[name] = depth
+	}
+	return fields
+}
+
+// fieldByIndex returns the nested field of the struct, allocating the nil embedded struct pointers on the way
+//
+// Parameters:
+//
+//   - value: The addressable struct value
+//   - index: The index sequence of the field
+//
+// Returns:
+//
+//   - reflect.Value: The field value
+//   - error: The error if an embedded struct pointer cannot be allocated
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, error) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, gojsondecoder.ErrNilDestination
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, nil
+}
+
+// hasCustomUnmarshaler reports whether the value decodes itself, so its members cannot be decoded on their own
+//
+// Parameters:
+//
+//   - value: The addressable value
+//
+// Returns:
+//
+//   - bool: Whether the value implements json.Unmarshaler or encoding.TextUnmarshaler
+func hasCustomUnmarshaler(value reflect.Value) bool {
+	if value.Kind() == reflect.Ptr {
+		return false
+	}
+	ptrType := reflect.PointerTo(value.Type())
+	return ptrType.Implements(unmarshalerType) || ptrType.Implements(textUnmarshalerType)
+}
+
+// findField returns the index of the struct field matching the member name, as encoding/json does: the exact match
+// first, then the first case-insensitive match in declaration order
+//
+// Parameters:
+//
+//   - fields: The field indexes by JSON name
+//   - name: The member name
+//
+// Returns:
+//
+//   - []int: The field index
+//   - bool: Whether a field matches
+func findField(fields map[string][]int, name string) ([]int, bool) {
+	if index, ok := fields[name]; ok {
+		return index, true
+	}
+
+	var match []int
+	for fieldName, index := range fields {
+		if strings.EqualFold(fieldName, name) && (match == nil || slices.Compare(index, match) < 0) {
+			match = index
+		}
+	}
+	return match, match != nil
+}
+
+// sortedKeys returns the keys of the map in ascending order, so the errors are collected deterministically
+//
+// Parameters:
+//
+//   - members: The map
+//
+// Returns:
+//
+//   - []string: The sorted keys
+func sortedKeys(members map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
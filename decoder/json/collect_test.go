@@ -0,0 +1,95 @@
+package json
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type collectItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type collectBody struct {
+	Items []collectItem `json:"items"`
+	Count int           `json:"count"`
+}
+
+type foldedBody struct {
+	First  int    `json:"Value"`
+	Second string `json:"VALUE"`
+}
+
+func TestUnmarshalAll(t *testing.T) {
+	tests := []struct {
+		name                  string
+		data                  string
+		dest                  any
+		disallowUnknownFields bool
+		wantPaths             []string
+		wantUnknownFields     []string
+	}{
+		{
+			name: "decodes the valid data",
+			data: `{"items":[{"id":1,"name":"a"}],"count":1}`,
+			dest: &collectBody{},
+		},
+		{
+			name:      "collects every type mismatch",
+			data:      `{"items":[{"id":"x","name":"a"},{"id":2,"name":3}],"count":"y"}`,
+			dest:      &collectBody{},
+			wantPaths: []string{"/items/0/id", "/items/1/name", "/count"},
+		},
+		{
+			name:                  "collects the unknown fields",
+			data:                  `{"items":[{"id":1,"extra":true}],"other":1}`,
+			dest:                  &collectBody{},
+			disallowUnknownFields: true,
+			wantPaths:             []string{"/items/0/extra", "/other"},
+			wantUnknownFields:     []string{"/items/0/extra", "/other"},
+		},
+		{
+			name:      "matches the first field in declaration order without an exact match",
+			data:      `{"value":"x"}`,
+			dest:      &foldedBody{},
+			wantPaths: []string{"/value"},
+		},
+		{
+			name: "prefers the exact match",
+			data: `{"VALUE":"x"}`,
+			dest: &foldedBody{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				// Repeat the decoding, so a nondeterministic match is detected
+				for range 10 {
+					err := UnmarshalAll([]byte(test.data), test.dest, test.disallowUnknownFields)
+
+					var paths, unknownFields []string
+					var decodeErrs gojsondecoder.DecodeErrors
+					if err != nil && !errors.As(err, &decodeErrs) {
+						t.Fatalf("error = %v, want DecodeErrors", err)
+					}
+					for _, decodeErr := range decodeErrs {
+						paths = append(paths, decodeErr.Path)
+						if errors.Is(decodeErr, gojsondecoder.ErrUnknownField) {
+							unknownFields = append(unknownFields, decodeErr.Path)
+						}
+					}
+					if !slices.Equal(paths, test.wantPaths) {
+						t.Fatalf("error paths = %v, want %v", paths, test.wantPaths)
+					}
+					if !slices.Equal(unknownFields, test.wantUnknownFields) {
+						t.Fatalf("unknown fields = %v, want %v", unknownFields, test.wantUnknownFields)
+					}
+				}
+			},
+		)
+	}
+}
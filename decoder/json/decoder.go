@@ -1,24 +1,130 @@
 package json
 
 import (
-	"encoding/json"
+	"context"
 	"io"
 
 	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
 )
 
 type (
 	// Decoder struct
-	Decoder struct{}
+	Decoder struct {
+		errorMode     gojsondecoder.ErrorMode
+		strictOptions *gojsondecoder.StrictOptions
+		limits        *gojsondecoder.Limits
+	}
+
+	// Options are the additional settings for the decoder implementations
+	Options struct {
+		// errorMode is how the errors found in a document are reported
+		errorMode gojsondecoder.ErrorMode
+
+		// strictOptions are the strictness settings applied to the documents
+		strictOptions *gojsondecoder.StrictOptions
+
+		// limits are the resource limits applied to the documents while they are read
+		limits *gojsondecoder.Limits
+	}
+
+	// Option sets an optional setting of the Options
+	Option func(options *Options)
 )
 
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - opts: the optional settings, such as WithErrorMode, WithStrictOptions and WithLimits
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(opts ...Option) *Options {
+	options := &Options{
+		errorMode: gojsondecoder.ErrorModeFirst,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithErrorMode sets how the errors found in a document are reported, ErrorModeFirst by default
+//
+// Parameters:
+//
+//   - errorMode: how the errors found in a document are reported
+//
+// Returns:
+//
+//   - Option: the option
+func WithErrorMode(errorMode gojsondecoder.ErrorMode) Option {
+	return func(options *Options) {
+		options.errorMode = errorMode
+	}
+}
+
+// WithStrictOptions sets the strictness settings applied to the documents, without them each decoder implementation
+// keeps its encoding/json behavior
+//
+// Parameters:
+//
+//   - strictOptions: the strictness settings
+//
+// Returns:
+//
+//   - Option: the option
+func WithStrictOptions(strictOptions *gojsondecoder.StrictOptions) Option {
+	return func(options *Options) {
+		options.strictOptions = strictOptions
+	}
+}
+
+// WithLimits sets the resource limits applied to the documents while they are read, there are no limits without
+// them
+//
+// Parameters:
+//
+//   - limits: the resource limits
+//
+// Returns:
+//
+//   - Option: the option
+func WithLimits(limits *gojsondecoder.Limits) Option {
+	return func(options *Options) {
+		options.limits = limits
+	}
+}
+
 // NewDecoder creates a new JSON decoder
 //
+// Parameters:
+//
+//   - options: the additional settings for the decoder implementation, nil stops at the first error and allows the
+//     unknown fields
+//
 // Returns:
 //
-//   - *Decoder: The default decoder
-func NewDecoder() *Decoder {
-	return &Decoder{}
+//   - *Decoder: The decoder
+func NewDecoder(options *Options) *Decoder {
+	// Initialize the error mode, the strictness settings and the limits
+	errorMode := gojsondecoder.ErrorModeFirst
+	var strictOptions *gojsondecoder.StrictOptions
+	var limits *gojsondecoder.Limits
+	if options != nil {
+		errorMode = options.errorMode
+		strictOptions = options.strictOptions
+		limits = options.limits
+	}
+
+	return &Decoder{
+		errorMode:     errorMode,
+		strictOptions: strictOptions,
+		limits:        limits,
+	}
 }
 
 // Decode decodes the JSON body from an any value and stores it in the destination
@@ -61,6 +167,25 @@ func (d Decoder) Decode(
 func (d Decoder) DecodeReader(
 	reader io.Reader,
 	dest any,
+) error {
+	return d.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes the JSON body and stores it in the destination, stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (d Decoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
 ) error {
 	// Check the reader
 	if reader == nil {
@@ -72,12 +197,79 @@ func (d Decoder) DecodeReader(
 		return gojsondecoder.ErrNilDestination
 	}
 
-	// Get the body of the request
-	body, err := io.ReadAll(reader)
-	if err != nil {
+	// Read the body into a pooled buffer, checking the context and the limits while it is read
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	if _, err := buffer.ReadFrom(d.limits.Reader(contextReader)); err != nil {
+		return err
+	}
+
+	// Check the context before decoding the body
+	if err := context.Cause(ctx); err != nil {
 		return err
 	}
 
 	// Decode JSON body into destination
-	return json.Unmarshal(body, dest)
+	return decode(
+		buffer.Bytes(),
+		dest,
+		d.errorMode,
+		d.strictOptions,
+		d.strictOptions.DisallowUnknownFields(),
+	)
+}
+
+// decode checks the body against the strictness settings and decodes it into the destination, according to the
+// error mode
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The destination to store the decoded body
+//   - errorMode: How the errors found in the body are reported
+//   - strictOptions: The strictness settings, nil skips the checks
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//
+// Returns:
+//
+//   - error: The error if any
+func decode(
+	body []byte,
+	dest any,
+	errorMode gojsondecoder.ErrorMode,
+	strictOptions *gojsondecoder.StrictOptions,
+	disallowUnknownFields bool,
+) error {
+	// Check the body against the strictness settings
+	body, err := strictOptions.Apply(body, errorMode)
+	if err != nil {
+		return err
+	}
+
+	if errorMode == gojsondecoder.ErrorModeCollect {
+		return decodeCollect(body, dest, disallowUnknownFields)
+	}
+	return gojsondecoder.ToDecodeError(body, Unmarshal(body, dest, disallowUnknownFields))
+}
+
+// decodeCollect decodes the body into the destination collecting every error, and validates the destination
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The destination to store the decoded body
+//   - disallowUnknownFields: Whether the object members that do not match a struct field are reported
+//
+// Returns:
+//
+//   - error: The DecodeErrors if any, or the *DecodeError if the body is not valid JSON
+func decodeCollect(body []byte, dest any, disallowUnknownFields bool) error {
+	// The destination is not validated if the body could not be decoded at all
+	err := UnmarshalAll(body, dest, disallowUnknownFields)
+	if _, ok := err.(*gojsondecoder.DecodeError); ok {
+		return err
+	}
+	return gojsondecoder.ToDecodeError(body, gojsondecoder.NewDecodeErrors(err, gojsondecoder.Validate(dest)))
 }
@@ -0,0 +1,159 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type strictBody struct {
+	Name string `json:"name"`
+}
+
+func TestDecoderStrictOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		strictOptions *gojsondecoder.StrictOptions
+		body          string
+		want          string
+		wantErr       error
+	}{
+		{
+			name: "keeps the last duplicate key by default",
+			body: `{"name":"a","name":"b"}`,
+			want: "b",
+		},
+		{
+			name:          "keeps the first duplicate key",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysFirstWins, false, false),
+			body:          `{"name":"a","name":"b"}`,
+			want:          "a",
+		},
+		{
+			name:          "rejects the duplicate keys",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysError, false, false),
+			body:          `{"name":"a","name":"b"}`,
+			wantErr:       gojsondecoder.ErrDuplicateKey,
+		},
+		{
+			name:          "ignores the unknown fields",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          `{"name":"a","unknown":1}`,
+			want:          "a",
+		},
+		{
+			name:          "rejects the unknown fields",
+			strictOptions: gojsondecoder.NewStrictOptions(true, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          `{"name":"a","unknown":1}`,
+			wantErr:       gojsondecoder.ErrUnknownField,
+		},
+		{
+			name:          "rejects the trailing data",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, true, false),
+			body:          `{"name":"a"} {"name":"b"}`,
+			wantErr:       gojsondecoder.ErrTrailingData,
+		},
+		{
+			name:          "replaces the invalid UTF-8 sequences",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          "{\"name\":\"a\xff\"}",
+			want:          "a�",
+		},
+		{
+			name:          "rejects the invalid UTF-8 sequences",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, false, true),
+			body:          "{\"name\":\"a\xff\"}",
+			wantErr:       gojsondecoder.ErrInvalidUTF8,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewDecoder(NewOptions(WithStrictOptions(test.strictOptions)))
+				var dest strictBody
+				err := decoder.DecodeReader(strings.NewReader(test.body), &dest)
+				if test.wantErr != nil {
+					if !errors.Is(err, test.wantErr) {
+						t.Errorf("error = %v, want %v", err, test.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				if dest.Name != test.want {
+					t.Errorf("name = %q, want %q", dest.Name, test.want)
+				}
+			},
+		)
+	}
+}
+
+// newBlockedReader returns a reader that blocks after the data until it is closed at the end of the test, its
+// blocked reads can be interrupted with read deadlines
+func newBlockedReader(t *testing.T, data string) *os.File {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(
+		func() {
+			_ = writer.Close()
+			_ = reader.Close()
+		},
+	)
+	if _, err = writer.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func TestDecodeReaderContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		decoder gojsondecoder.ContextDecoder
+	}{
+		{
+			name:    "interrupts the decoder blocked on the body",
+			decoder: NewDecoder(nil),
+		},
+		{
+			name:    "interrupts the stream decoder blocked on the body",
+			decoder: NewStreamDecoder(nil),
+		},
+		{
+			name:    "interrupts the array decoder blocked on the body",
+			decoder: NewArrayDecoder(nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				reader := newBlockedReader(t, `[{"name":"a"},{"name":`)
+				time.AfterFunc(10*time.Millisecond, cancel)
+
+				var dest []strictBody
+				done := make(chan error, 1)
+				go func() {
+					done <- test.decoder.DecodeReaderContext(ctx, reader, &dest)
+				}()
+				select {
+				case err := <-done:
+					if !errors.Is(err, context.Canceled) {
+						t.Errorf("error = %v, want %v", err, context.Canceled)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("decoder still blocked after the context was canceled")
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,20 @@
+package json
+
+import (
+	"errors"
+)
+
+const (
+	ErrDecodeArrayElement = "failed to decode array element %d: %w"
+)
+
+var (
+	ErrExpectedArrayStart = errors.New("expected the start of a JSON array")
+	ErrExpectedArrayEnd   = errors.New("expected the end of the JSON array")
+
+	// errStopped is returned internally when the caller stops an iteration
+	errStopped = errors.New("iteration stopped by the caller")
+
+	// errElementTooLarge is returned internally when the element being read reaches its size limit
+	errElementTooLarge = errors.New("array element reached its size limit")
+)
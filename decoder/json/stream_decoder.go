@@ -1,24 +1,71 @@
 package json
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 
 	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+)
+
+var (
+	// defaultStreamStrictOptions are the strictness settings matching the json.Decoder settings of the stream
+	// decoder, the unknown fields are disallowed and the data after the top-level value is not read
+	defaultStreamStrictOptions = gojsondecoder.NewStrictOptions(
+		true,
+		gojsondecoder.DuplicateKeysLastWins,
+		false,
+		false,
+	)
 )
 
 type (
 	// StreamDecoder is the JSON decoder struct
-	StreamDecoder struct{}
+	StreamDecoder struct {
+		errorMode     gojsondecoder.ErrorMode
+		strictOptions *gojsondecoder.StrictOptions
+		limits        *gojsondecoder.Limits
+	}
+
+	// readErrorReader is the reader that keeps the error returned by the underlying reader, so the read errors, such
+	// as a broken connection, are returned as is instead of as decoding errors of the body
+	readErrorReader struct {
+		reader io.Reader
+		err    error
+	}
 )
 
-// NewStreamDecoder creates a new JSON decoder
+// NewStreamDecoder creates a new JSON decoder that decodes the body while it is read. The collect error mode and the
+// strictness settings need the whole document, so with any of them the body is read into memory before it is
+// decoded, as the Decoder does. Use the limits to bound the memory used by the large bodies
+//
+// Parameters:
+//
+//   - options: the additional settings for the decoder implementation, nil stops at the first error and disallows
+//     the unknown fields
 //
 // Returns:
 //
-//   - *StreamDecoder: The default decoder
-func NewStreamDecoder() *StreamDecoder {
-	return &StreamDecoder{}
+//   - *StreamDecoder: The decoder
+func NewStreamDecoder(options *Options) *StreamDecoder {
+	// Initialize the error mode, the strictness settings and the limits
+	errorMode := gojsondecoder.ErrorModeFirst
+	var strictOptions *gojsondecoder.StrictOptions
+	var limits *gojsondecoder.Limits
+	if options != nil {
+		errorMode = options.errorMode
+		strictOptions = options.strictOptions
+		limits = options.limits
+	}
+
+	return &StreamDecoder{
+		errorMode:     errorMode,
+		strictOptions: strictOptions,
+		limits:        limits,
+	}
 }
 
 // Decode decodes the JSON body from an any value and stores it in the destination
@@ -61,6 +108,25 @@ func (s StreamDecoder) Decode(
 func (s StreamDecoder) DecodeReader(
 	reader io.Reader,
 	dest any,
+) error {
+	return s.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes a JSON body from a reader into a destination, stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (s StreamDecoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
 ) error {
 	// Check the reader
 	if reader == nil {
@@ -72,10 +138,78 @@ func (s StreamDecoder) DecodeReader(
 		return gojsondecoder.ErrNilDestination
 	}
 
+	// Check the context and the limits while the body is read, the context reader wraps the given reader so its
+	// blocked reads can be interrupted
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	readErrReader := &readErrorReader{reader: contextReader}
+	reader = s.limits.Reader(readErrReader)
+
+	// The collect mode and the strictness checks need the whole body
+	if s.errorMode == gojsondecoder.ErrorModeCollect || s.strictOptions != nil {
+		buffer := gojsonbuffer.Get(0)
+		defer gojsonbuffer.Put(buffer)
+		if _, err := buffer.ReadFrom(reader); err != nil {
+			return err
+		}
+
+		// Check the context before decoding the body
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+
+		// Apply the default stream settings if there are no strictness settings
+		strictOptions := s.strictOptions
+		if strictOptions == nil {
+			strictOptions = defaultStreamStrictOptions
+		}
+		return decode(buffer.Bytes(), dest, s.errorMode, strictOptions, strictOptions.DisallowUnknownFields())
+	}
+
 	// Create the stream decoder
 	decoder := json.NewDecoder(reader)
 	decoder.DisallowUnknownFields()
 
-	// Decode JSON body into destination
-	return decoder.Decode(dest)
+	// Decode JSON body into destination, the body is not kept so only the offset of the errors is known
+	if err := decoder.Decode(dest); err != nil {
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		if readErrReader.isReadError(err) {
+			return err
+		}
+		return gojsondecoder.ToDecodeError(nil, err)
+	}
+	return nil
+}
+
+// Read reads from the underlying reader, keeping its error other than io.EOF
+//
+// Parameters:
+//
+//   - p: the buffer to read into
+//
+// Returns:
+//
+//   - int: the number of bytes read
+//   - error: the error if any
+func (r *readErrorReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+	return n, err
+}
+
+// isReadError returns whether the error was returned by the underlying reader
+//
+// Parameters:
+//
+//   - err: the error returned while decoding
+//
+// Returns:
+//
+//   - bool: whether the error is the read error
+func (r *readErrorReader) isReadError(err error) bool {
+	return r.err != nil && errors.Is(err, r.err)
 }
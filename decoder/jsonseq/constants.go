@@ -0,0 +1,16 @@
+package jsonseq
+
+import (
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+const (
+	// ContentType is the media type of JSON text sequences
+	ContentType = gojsonsequence.JSONSeqContentType
+
+	// RecordSeparator is the ASCII RS character that precedes each JSON text
+	RecordSeparator = gojsonsequence.RecordSeparator
+
+	// DefaultMaxRecordSize is the default maximum size in bytes of a record
+	DefaultMaxRecordSize = 1024 * 1024
+)
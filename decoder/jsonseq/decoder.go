@@ -0,0 +1,362 @@
+package jsonseq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+	"unicode"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+type (
+	// Decoder is the RFC 7464 JSON text sequences implementation of the Decoder interface, each record is decoded
+	// with the record decoder. Malformed records are reported and skipped, the decoding resynchronizes on the next
+	// record separator
+	Decoder struct {
+		recordDecoder gojsondecoder.Decoder
+		maxRecordSize int
+	}
+
+	// Options are the additional settings for the decoder implementation
+	Options struct {
+		// recordDecoder is the decoder used to decode each record
+		recordDecoder gojsondecoder.Decoder
+
+		// maxRecordSize is the maximum size in bytes of a record
+		maxRecordSize int
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - recordDecoder: the decoder called with each record once its RS prefix and trailing line feed are stripped,
+//     nil uses the plain JSON decoder. maxRecordSize only bounds the bytes buffered per record, the nesting depth
+//     and the other limits of a record are the ones set on this decoder
+//   - maxRecordSize: the maximum size in bytes of a record, 0 uses DefaultMaxRecordSize
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	recordDecoder gojsondecoder.Decoder,
+	maxRecordSize int,
+) *Options {
+	return &Options{
+		recordDecoder: recordDecoder,
+		maxRecordSize: maxRecordSize,
+	}
+}
+
+// NewDecoder creates a new JSON text sequences decoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the decoder implementation
+//
+// Returns:
+//
+//   - *Decoder: the new Decoder instance
+func NewDecoder(options *Options) *Decoder {
+	// Initialize the settings
+	var recordDecoder gojsondecoder.Decoder
+	maxRecordSize := 0
+	if options != nil {
+		recordDecoder = options.recordDecoder
+		maxRecordSize = options.maxRecordSize
+	}
+
+	// Initialize the record decoder and the maximum record size
+	if recordDecoder == nil {
+		recordDecoder = gojsondecoderjson.NewDecoder(nil)
+	}
+	if maxRecordSize <= 0 {
+		maxRecordSize = DefaultMaxRecordSize
+	}
+
+	return &Decoder{
+		recordDecoder: recordDecoder,
+		maxRecordSize: maxRecordSize,
+	}
+}
+
+// Decode decodes the JSON text sequence from an any value and appends each record to the destination slice
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) Decode(
+	body any,
+	dest any,
+) error {
+	// Check the body
+	if body == nil {
+		return gojsondecoder.ErrNilBody
+	}
+
+	// Check the body type
+	reader, err := gojsondecoder.ToReader(body)
+	if err != nil {
+		return err
+	}
+	return d.DecodeReader(reader, dest)
+}
+
+// DecodeReader decodes the JSON text sequence from a reader and appends each valid record to the destination
+// slice. The malformed records are skipped and returned joined as *RecordError
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeReader(
+	reader io.Reader,
+	dest any,
+) error {
+	return d.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes the JSON text sequence from a reader and appends each valid record to the destination
+// slice, stopping once the context is done. The records decoded before are kept in the destination slice
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context is joined to the record errors if it is done
+func (d Decoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
+) error {
+	// Check the reader
+	if reader == nil {
+		return gojsondecoder.ErrNilReader
+	}
+
+	// Check the decoder destination
+	if dest == nil {
+		return gojsondecoder.ErrNilDestination
+	}
+
+	// Check the destination is a pointer to a slice
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return gojsondecoder.ErrDestinationNotSlicePointer
+	}
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	// Decode each record into a new element
+	var recordErrors []error
+	err := d.scanRecords(ctx, reader, func(record int, start gojsondecoder.Position, data []byte, scanErr error) bool {
+		if scanErr != nil {
+			recordErrors = append(recordErrors, newRecordError(record, start, nil, scanErr))
+			return true
+		}
+
+		elemValue := reflect.New(elemType)
+		if decodeErr := gojsondecoder.DecodeIntoValue(d.recordDecoder, data, elemValue); decodeErr != nil {
+			recordErrors = append(recordErrors, newRecordError(record, start, data, decodeErr))
+			return true
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elemValue.Elem()))
+		return true
+	})
+	if err != nil {
+		recordErrors = append(recordErrors, err)
+	}
+	return errors.Join(recordErrors...)
+}
+
+// ContentType returns the media type of the decoded body
+//
+// Returns:
+//
+//   - string: the media type
+func (d Decoder) ContentType() string {
+	return ContentType
+}
+
+// scanRecords reads the reader record by record and calls the function with each non-empty record, or with the
+// error found while delimiting it, until the context is done. The records larger than the maximum record size are
+// discarded while they are read and reported with ErrRecordTooLarge, the scan resynchronizes on the next record
+// separator
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the records from
+//   - fn: The function to call with the record number, the position of the record in the body, the record bytes
+//     and the delimiting error, it returns false to stop the scan. The bytes are only valid until the function
+//     returns
+//
+// Returns:
+//
+//   - error: The read error if any, the cause of the context if it is done
+func (d Decoder) scanRecords(
+	ctx context.Context,
+	reader io.Reader,
+	fn func(record int, start gojsondecoder.Position, data []byte, scanErr error) bool,
+) error {
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	splitter := gojsonsequence.NewSplitter(contextReader, RecordSeparator, d.maxRecordSize)
+
+	record := 0
+	for segmentIndex := 0; ; segmentIndex++ {
+		segment, err := splitter.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if ctxErr := context.Cause(ctx); ctxErr != nil {
+				return ctxErr
+			}
+			return NewRecordError(record+1, err)
+		}
+
+		// Check the context
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+
+		// Report the oversized records without stopping the scan
+		if segment.TooLarge {
+			record++
+			if !fn(record, segment.Start, nil, gojsondecoder.ErrRecordTooLarge) {
+				return nil
+			}
+			continue
+		}
+
+		// Skip the empty records
+		content := segment.Data
+		data := bytes.TrimSpace(content)
+		if len(data) == 0 {
+			continue
+		}
+		record++
+
+		// Data before the first record separator is malformed
+		if segmentIndex == 0 {
+			if !fn(record, segment.Start, nil, ErrMissingRecordSeparator) {
+				return nil
+			}
+			continue
+		}
+
+		// Top-level numbers, true, false and null must be followed by whitespace, otherwise they may be truncated
+		var scanErr error
+		isScalar := data[0] != '{' && data[0] != '[' && data[0] != '"'
+		if isScalar && len(bytes.TrimRight(content, " \t\r\n")) == len(content) {
+			scanErr = ErrTruncatedRecord
+		}
+
+		// Locate the record after the leading whitespace
+		leadingSpace := len(content) - len(bytes.TrimLeftFunc(content, unicode.IsSpace))
+		if !fn(record, segment.Start.Advance(content[:leadingSpace]), data, scanErr) {
+			return nil
+		}
+	}
+}
+
+// DecodeSeq returns a sequence that decodes the JSON text sequence record by record, without reading the whole
+// body
+//
+// A malformed record is yielded with a *RecordError and the sequence continues with the next record. The caller can
+// stop the sequence at any time by breaking the loop. Read errors are always yielded last
+//
+// Parameters:
+//
+//   - decoder: The JSON text sequences decoder, nil uses the default decoder
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded records and errors
+func DecodeSeq[T any](
+	decoder *Decoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return DecodeSeqContext[T](context.Background(), decoder, reader)
+}
+
+// DecodeSeqContext returns a sequence that decodes the JSON text sequence record by record, as DecodeSeq does, until
+// the context is done. The cause of the context is yielded last once it is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - decoder: The JSON text sequences decoder, nil uses the default decoder
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded records and errors
+func DecodeSeqContext[T any](
+	ctx context.Context,
+	decoder *Decoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// Check the reader
+		if reader == nil {
+			yield(zero, gojsondecoder.ErrNilReader)
+			return
+		}
+
+		// Initialize the decoder
+		if decoder == nil {
+			decoder = NewDecoder(nil)
+		}
+
+		// Decode each record
+		err := decoder.scanRecords(ctx, reader, func(
+			record int,
+			start gojsondecoder.Position,
+			data []byte,
+			scanErr error,
+		) bool {
+			if scanErr != nil {
+				return yield(zero, newRecordError(record, start, nil, scanErr))
+			}
+
+			var value T
+			if decodeErr := gojsondecoder.DecodeIntoValue(
+				decoder.recordDecoder,
+				data,
+				reflect.ValueOf(&value),
+			); decodeErr != nil {
+				return yield(zero, newRecordError(record, start, data, decodeErr))
+			}
+			return yield(value, nil)
+		})
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package jsonseq
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type record struct {
+	ID int `json:"id"`
+}
+
+func TestDecoderDecodeReader(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		maxRecordSize int
+		want          []int
+		wantErrs      []error
+		wantRecords   []int
+	}{
+		{
+			name:  "decodes every record",
+			input: "\x1e{\"id\":1}\n\x1e{\"id\":2}\n",
+			want:  []int{1, 2},
+		},
+		{
+			name:  "skips the empty records",
+			input: "\x1e\n\x1e{\"id\":1}\n\x1e  \x1e{\"id\":2}\n",
+			want:  []int{1, 2},
+		},
+		{
+			name:  "accepts the pretty printed records",
+			input: "\x1e{\n  \"id\": 1\n}\n",
+			want:  []int{1},
+		},
+		{
+			name:        "reports the data before the first record separator",
+			input:       "{\"id\":0}\n\x1e{\"id\":1}\n",
+			want:        []int{1},
+			wantErrs:    []error{ErrMissingRecordSeparator},
+			wantRecords: []int{1},
+		},
+		{
+			name:        "resynchronizes after a malformed record",
+			input:       "\x1e{\"id\":1}\n\x1e{\"id\":\n\x1e{\"id\":3}\n",
+			want:        []int{1, 3},
+			wantErrs:    []error{nil},
+			wantRecords: []int{2},
+		},
+		{
+			name:          "resynchronizes after an oversized record",
+			input:         "\x1e{\"id\":1}\n\x1e{\"id\":2, \"padding\": \"" + strings.Repeat("x", 64) + "\"}\n\x1e{\"id\":3}\n",
+			maxRecordSize: 32,
+			want:          []int{1, 3},
+			wantErrs:      []error{gojsondecoder.ErrRecordTooLarge},
+			wantRecords:   []int{2},
+		},
+		{
+			name:          "resynchronizes after an oversized record larger than the read buffer",
+			input:         "\x1e" + strings.Repeat(" ", 10000) + "{\"id\":1}\n\x1e{\"id\":2}\n",
+			maxRecordSize: 4096,
+			want:          []int{2},
+			wantErrs:      []error{gojsondecoder.ErrRecordTooLarge},
+			wantRecords:   []int{1},
+		},
+		{
+			name:        "reports the truncated scalar records",
+			input:       "\x1e1\x1e2\n",
+			want:        []int{2},
+			wantErrs:    []error{ErrTruncatedRecord},
+			wantRecords: []int{1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewDecoder(NewOptions(nil, test.maxRecordSize))
+				var dest []any
+				err := decoder.DecodeReader(strings.NewReader(test.input), &dest)
+
+				// Check the decoded records
+				var got []int
+				for _, value := range dest {
+					switch typed := value.(type) {
+					case map[string]any:
+						got = append(got, int(typed["id"].(float64)))
+					case float64:
+						got = append(got, int(typed))
+					}
+				}
+				if !slices.Equal(got, test.want) {
+					t.Errorf("decoded records = %v, want %v", got, test.want)
+				}
+
+				// Check the record errors
+				var recordErrs []*RecordError
+				if err != nil {
+					for _, joinedErr := range err.(interface{ Unwrap() []error }).Unwrap() {
+						var recordErr *RecordError
+						if !errors.As(joinedErr, &recordErr) {
+							t.Fatalf("error %v is not a *RecordError", joinedErr)
+						}
+						recordErrs = append(recordErrs, recordErr)
+					}
+				}
+				if len(recordErrs) != len(test.wantRecords) {
+					t.Fatalf("errors = %v, want %d record errors", err, len(test.wantRecords))
+				}
+				for i, recordErr := range recordErrs {
+					if recordErr.Record != test.wantRecords[i] {
+						t.Errorf("error %d record = %d, want %d", i, recordErr.Record, test.wantRecords[i])
+					}
+					if test.wantErrs[i] != nil && !errors.Is(recordErr, test.wantErrs[i]) {
+						t.Errorf("error %d = %v, want %v", i, recordErr, test.wantErrs[i])
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestDecodeSeq(t *testing.T) {
+	input := "\x1e{\"id\":1}\n\x1e{\"id\":}\n\x1e{\"id\":3}\n"
+	var got []int
+	var errs []error
+	for value, err := range DecodeSeq[record](nil, strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, value.ID)
+	}
+	if !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("records = %v, want [1 3]", got)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want 1 error", errs)
+	}
+
+	// The error is located in the body
+	var decodeErr *gojsondecoder.DecodeError
+	if !errors.As(errs[0], &decodeErr) || decodeErr.Line != 2 {
+		t.Errorf("error = %v, want a *DecodeError on line 2", errs[0])
+	}
+}
@@ -0,0 +1,81 @@
+package jsonseq
+
+import (
+	"errors"
+	"fmt"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+const (
+	ErrDecodeRecord = "failed to decode record %d: %v"
+)
+
+var (
+	ErrMissingRecordSeparator = errors.New("data found before the record separator")
+	ErrTruncatedRecord        = errors.New("record is truncated")
+)
+
+type (
+	// RecordError is the error reported when a record is malformed, it carries the position of the record in the
+	// sequence
+	RecordError struct {
+		Record int
+		Err    error
+	}
+)
+
+// NewRecordError creates a new RecordError instance
+//
+// Parameters:
+//
+//   - record: the position of the record in the sequence, starting at 1
+//   - err: the error returned while decoding the record
+//
+// Returns:
+//
+//   - *RecordError: the new RecordError instance
+func NewRecordError(record int, err error) *RecordError {
+	return &RecordError{
+		Record: record,
+		Err:    err,
+	}
+}
+
+// newRecordError creates the error returned when a record is malformed, wrapping a *DecodeError located in the
+// sequence
+//
+// Parameters:
+//
+//   - record: the position of the record in the sequence, starting at 1
+//   - start: the position of the record in the body
+//   - data: the record bytes, nil if the record was not delimited
+//   - err: the error returned while decoding the record
+//
+// Returns:
+//
+//   - *RecordError: the new RecordError instance
+func newRecordError(record int, start gojsondecoder.Position, data []byte, err error) *RecordError {
+	if data == nil {
+		return NewRecordError(record, gojsondecoder.NewDecodeError("", start, nil, err))
+	}
+	return NewRecordError(record, gojsondecoder.NestDecodeError(data, err, "", start))
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: the error message
+func (r RecordError) Error() string {
+	return fmt.Sprintf(ErrDecodeRecord, r.Record, r.Err)
+}
+
+// Unwrap returns the wrapped error
+//
+// Returns:
+//
+//   - error: the wrapped error
+func (r RecordError) Unwrap() error {
+	return r.Err
+}
@@ -0,0 +1,298 @@
+package decoder
+
+import (
+	"io"
+)
+
+type (
+	// LimitKind is the resource bounded by a limit
+	LimitKind int
+
+	// Limits are the resource limits applied to the documents while they are read, so the hostile payloads are
+	// rejected before they are decoded. A zero limit means no limit
+	Limits struct {
+		// maxBytes is the maximum size in bytes of a document
+		maxBytes int64
+
+		// maxDepth is the maximum nesting depth of the objects and arrays
+		maxDepth int
+
+		// maxMembers is the maximum number of members of an object
+		maxMembers int
+
+		// maxElements is the maximum number of elements of an array
+		maxElements int
+
+		// maxStringLength is the maximum length in bytes of a string, as encoded in the document
+		maxStringLength int
+	}
+
+	// limitReader is the reader that scans the document while it is read, failing once a limit is exceeded
+	limitReader struct {
+		reader   io.Reader
+		limits   *Limits
+		position Position
+		read     int64
+		frames   []limitFrame
+		inString bool
+		escaped  bool
+		length   int
+		err      error
+	}
+
+	// limitFrame is an object or an array opened by the document
+	limitFrame struct {
+		isObject bool
+		count    int
+		empty    bool
+	}
+)
+
+const (
+	// LimitBytes bounds the size in bytes of a document
+	LimitBytes LimitKind = iota
+
+	// LimitDepth bounds the nesting depth of the objects and arrays
+	LimitDepth
+
+	// LimitMembers bounds the number of members of an object
+	LimitMembers
+
+	// LimitElements bounds the number of elements of an array
+	LimitElements
+
+	// LimitStringLength bounds the length in bytes of a string
+	LimitStringLength
+
+	// LimitElementSize bounds the size in bytes of an element of a streamed array
+	LimitElementSize
+)
+
+const (
+	// DefaultMaxBytes is the default maximum size in bytes of a document
+	DefaultMaxBytes = 10 << 20
+
+	// DefaultMaxDepth is the default maximum nesting depth, the same as the protobuf recursion limit
+	DefaultMaxDepth = 100
+
+	// DefaultMaxMembers is the default maximum number of members of an object
+	DefaultMaxMembers = 10_000
+
+	// DefaultMaxElements is the default maximum number of elements of an array
+	DefaultMaxElements = 100_000
+
+	// DefaultMaxStringLength is the default maximum length in bytes of a string
+	DefaultMaxStringLength = 1 << 20
+)
+
+// String returns the name of the limited resource
+//
+// Returns:
+//
+//   - string: the name
+func (l LimitKind) String() string {
+	switch l {
+	case LimitBytes:
+		return "size in bytes"
+	case LimitDepth:
+		return "nesting depth"
+	case LimitMembers:
+		return "number of object members"
+	case LimitElements:
+		return "number of array elements"
+	case LimitStringLength:
+		return "string length in bytes"
+	case LimitElementSize:
+		return "array element size in bytes"
+	default:
+		return "unknown limit"
+	}
+}
+
+// NewLimits creates a new Limits instance
+//
+// Parameters:
+//
+//   - maxBytes: the maximum size in bytes of a document, 0 means no limit
+//   - maxDepth: the maximum nesting depth of the objects and arrays, 0 means no limit
+//   - maxMembers: the maximum number of members of an object, 0 means no limit
+//   - maxElements: the maximum number of elements of an array, 0 means no limit
+//   - maxStringLength: the maximum length in bytes of a string, as encoded in the document, 0 means no limit
+//
+// Returns:
+//
+//   - *Limits: the new Limits instance
+func NewLimits(
+	maxBytes int64,
+	maxDepth int,
+	maxMembers int,
+	maxElements int,
+	maxStringLength int,
+) *Limits {
+	return &Limits{
+		maxBytes:        maxBytes,
+		maxDepth:        maxDepth,
+		maxMembers:      maxMembers,
+		maxElements:     maxElements,
+		maxStringLength: maxStringLength,
+	}
+}
+
+// NewDefaultLimits creates a new Limits instance with the default limits, suited for the public APIs
+//
+// Returns:
+//
+//   - *Limits: the new Limits instance
+func NewDefaultLimits() *Limits {
+	return NewLimits(
+		DefaultMaxBytes,
+		DefaultMaxDepth,
+		DefaultMaxMembers,
+		DefaultMaxElements,
+		DefaultMaxStringLength,
+	)
+}
+
+// Reader wraps the reader of a document, so the limits are checked while it is read. Once a limit is exceeded,
+// the reader stops reading and returns a *DecodeError wrapping a *LimitError, located at the offending byte. Nil
+// limits return the reader as is
+//
+// Parameters:
+//
+//   - reader: the reader of the document
+//
+// Returns:
+//
+//   - io.Reader: the limited reader
+func (l *Limits) Reader(reader io.Reader) io.Reader {
+	if l == nil || reader == nil {
+		return reader
+	}
+	return &limitReader{
+		reader:   reader,
+		limits:   l,
+		position: StartPosition,
+	}
+}
+
+// Read reads the document, failing once a limit is exceeded
+//
+// Parameters:
+//
+//   - p: the buffer to read into
+//
+// Returns:
+//
+//   - int: the number of bytes read
+//   - error: the error if any
+func (r *limitReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	// Read at most one byte more than allowed, so an exceeded size is detected without reading further
+	if maxBytes := r.limits.maxBytes; maxBytes > 0 && int64(len(p)) > maxBytes-r.read+1 {
+		p = p[:maxBytes-r.read+1]
+	}
+	n, err := r.reader.Read(p)
+
+	// Scan the bytes read
+	for i := 0; i < n; i++ {
+		if limitErr := r.scanByte(p[i]); limitErr != nil {
+			r.err = NewDecodeError("", r.position.Advance(p[:i]), nil, limitErr)
+			return i, r.err
+		}
+	}
+	r.position = r.position.Advance(p[:n])
+	return n, err
+}
+
+// scanByte scans the next byte of the document
+//
+// Parameters:
+//
+//   - c: the byte
+//
+// Returns:
+//
+//   - *LimitError: the exceeded limit, nil if none is exceeded
+func (r *limitReader) scanByte(c byte) *LimitError {
+	// Check the size
+	r.read++
+	if maxBytes := r.limits.maxBytes; maxBytes > 0 && r.read > maxBytes {
+		return NewLimitError(LimitBytes, maxBytes)
+	}
+
+	// Check the length of the strings
+	if r.inString {
+		switch {
+		case r.escaped:
+			r.escaped = false
+		case c == '\\':
+			r.escaped = true
+		case c == '"':
+			r.inString = false
+			return nil
+		}
+		r.length++
+		if maxLength := r.limits.maxStringLength; maxLength > 0 && r.length > maxLength {
+			return NewLimitError(LimitStringLength, int64(maxLength))
+		}
+		return nil
+	}
+
+	switch c {
+	case ' ', '\t', '\r', '\n':
+		return nil
+	}
+
+	// Count the first member or element of the enclosing object or array
+	if last := len(r.frames) - 1; last >= 0 && r.frames[last].empty && c != '}' && c != ']' {
+		r.frames[last].empty = false
+		if limitErr := r.countItem(last); limitErr != nil {
+			return limitErr
+		}
+	}
+
+	switch c {
+	case '{', '[':
+		r.frames = append(r.frames, limitFrame{isObject: c == '{', empty: true})
+		if maxDepth := r.limits.maxDepth; maxDepth > 0 && len(r.frames) > maxDepth {
+			return NewLimitError(LimitDepth, int64(maxDepth))
+		}
+	case '}', ']':
+		if len(r.frames) > 0 {
+			r.frames = r.frames[:len(r.frames)-1]
+		}
+	case ',':
+		if last := len(r.frames) - 1; last >= 0 {
+			return r.countItem(last)
+		}
+	case '"':
+		r.inString = true
+		r.length = 0
+	}
+	return nil
+}
+
+// countItem counts a member or an element of an open object or array
+//
+// Parameters:
+//
+//   - index: the index of the object or array frame
+//
+// Returns:
+//
+//   - *LimitError: the exceeded limit, nil if none is exceeded
+func (r *limitReader) countItem(index int) *LimitError {
+	frame := &r.frames[index]
+	frame.count++
+	if frame.isObject {
+		if maxMembers := r.limits.maxMembers; maxMembers > 0 && frame.count > maxMembers {
+			return NewLimitError(LimitMembers, int64(maxMembers))
+		}
+	} else if maxElements := r.limits.maxElements; maxElements > 0 && frame.count > maxElements {
+		return NewLimitError(LimitElements, int64(maxElements))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package decoder
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLimitsReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     *Limits
+		data       string
+		wantLimit  LimitKind
+		wantOffset int64
+		wantErr    bool
+	}{
+		{
+			name:   "reads the documents within the limits",
+			limits: NewLimits(64, 2, 2, 2, 8),
+			data:   `{"a":[1,2],"b":"12345678"}`,
+		},
+		{
+			name:   "reads the documents without limits",
+			limits: NewLimits(0, 0, 0, 0, 0),
+			data:   `{"a":[[[[1,2,3]]]],"b":"` + strings.Repeat("x", 1024) + `"}`,
+		},
+		{
+			name:       "rejects the oversized documents",
+			limits:     NewLimits(8, 0, 0, 0, 0),
+			data:       `{"a":"12345"}`,
+			wantLimit:  LimitBytes,
+			wantOffset: 8,
+			wantErr:    true,
+		},
+		{
+			name:       "rejects the deep documents",
+			limits:     NewLimits(0, 2, 0, 0, 0),
+			data:       `{"a":{"b":[1]}}`,
+			wantLimit:  LimitDepth,
+			wantOffset: 10,
+			wantErr:    true,
+		},
+		{
+			name:       "rejects the objects with too many members",
+			limits:     NewLimits(0, 0, 2, 0, 0),
+			data:       `{"a":1,"b":2,"c":3}`,
+			wantLimit:  LimitMembers,
+			wantOffset: 12,
+			wantErr:    true,
+		},
+		{
+			name:       "rejects the arrays with too many elements",
+			limits:     NewLimits(0, 0, 0, 2, 0),
+			data:       `[1, 2, 3]`,
+			wantLimit:  LimitElements,
+			wantOffset: 5,
+			wantErr:    true,
+		},
+		{
+			name:       "rejects the long strings",
+			limits:     NewLimits(0, 0, 0, 0, 4),
+			data:       `{"a":"12345"}`,
+			wantLimit:  LimitStringLength,
+			wantOffset: 10,
+			wantErr:    true,
+		},
+		{
+			name:   "does not end the strings at the escaped quotes",
+			limits: NewLimits(0, 0, 1, 0, 0),
+			data:   `{"a":"\",\"b\":1"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				_, err := io.ReadAll(test.limits.Reader(strings.NewReader(test.data)))
+				if !test.wantErr {
+					if err != nil {
+						t.Fatalf("error = %v, want nil", err)
+					}
+					return
+				}
+
+				var limitErr *LimitError
+				var decodeErr *DecodeError
+				if !errors.As(err, &limitErr) || !errors.As(err, &decodeErr) {
+					t.Fatalf("error = %v, want a *DecodeError wrapping a *LimitError", err)
+				}
+				if limitErr.Limit != test.wantLimit {
+					t.Errorf("limit = %v, want %v", limitErr.Limit, test.wantLimit)
+				}
+				if decodeErr.Offset != test.wantOffset {
+					t.Errorf("offset = %d, want %d", decodeErr.Offset, test.wantOffset)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,10 @@
+package ndjson
+
+import (
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+const (
+	// ContentType is the media type of newline-delimited JSON
+	ContentType = gojsonsequence.NDJSONContentType
+)
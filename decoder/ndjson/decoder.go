@@ -0,0 +1,369 @@
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+	"unicode"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+type (
+	// ErrorPolicy is the policy applied when a record cannot be decoded
+	ErrorPolicy int
+
+	// Decoder is the newline-delimited JSON (JSON Lines) decoder, each line is decoded as one record with the
+	// record decoder
+	Decoder struct {
+		recordDecoder gojsondecoder.Decoder
+		errorPolicy   ErrorPolicy
+		maxLineSize   int
+	}
+
+	// Options are the additional settings for the decoder implementation
+	Options struct {
+		// recordDecoder is the decoder used to decode each record
+		recordDecoder gojsondecoder.Decoder
+
+		// errorPolicy is the policy applied when a record cannot be decoded
+		errorPolicy ErrorPolicy
+
+		// maxLineSize is the maximum size in bytes of a line
+		maxLineSize int
+	}
+)
+
+const (
+	// ErrorPolicyAbort stops the decoding after the first record that cannot be decoded
+	ErrorPolicyAbort ErrorPolicy = iota
+
+	// ErrorPolicySkip reports the records that cannot be decoded and continues with the next line
+	ErrorPolicySkip
+)
+
+const (
+	// DefaultMaxLineSize is the default maximum size in bytes of a line
+	DefaultMaxLineSize = 1024 * 1024
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - recordDecoder: the decoder used to decode each record, nil uses the plain JSON decoder. Use the protojson
+//     decoder for records with proto.Message fields, and the decoder Limits to bound the resources of each record
+//   - errorPolicy: the policy applied when a record cannot be decoded
+//   - maxLineSize: the maximum size in bytes of a line, 0 uses DefaultMaxLineSize
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	recordDecoder gojsondecoder.Decoder,
+	errorPolicy ErrorPolicy,
+	maxLineSize int,
+) *Options {
+	return &Options{
+		recordDecoder: recordDecoder,
+		errorPolicy:   errorPolicy,
+		maxLineSize:   maxLineSize,
+	}
+}
+
+// NewDecoder creates a new NDJSON decoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the decoder implementation
+//
+// Returns:
+//
+//   - *Decoder: the new Decoder instance
+func NewDecoder(options *Options) *Decoder {
+	// Initialize the settings
+	var recordDecoder gojsondecoder.Decoder
+	errorPolicy := ErrorPolicyAbort
+	maxLineSize := 0
+	if options != nil {
+		recordDecoder = options.recordDecoder
+		errorPolicy = options.errorPolicy
+		maxLineSize = options.maxLineSize
+	}
+
+	// Initialize the record decoder and the maximum line size
+	if recordDecoder == nil {
+		recordDecoder = gojsondecoderjson.NewDecoder(nil)
+	}
+	if maxLineSize <= 0 {
+		maxLineSize = DefaultMaxLineSize
+	}
+
+	return &Decoder{
+		recordDecoder: recordDecoder,
+		errorPolicy:   errorPolicy,
+		maxLineSize:   maxLineSize,
+	}
+}
+
+// Decode decodes the NDJSON body from an any value and appends each record to the destination slice
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) Decode(
+	body any,
+	dest any,
+) error {
+	// Check the body
+	if body == nil {
+		return gojsondecoder.ErrNilBody
+	}
+
+	// Check the body type
+	reader, err := gojsondecoder.ToReader(body)
+	if err != nil {
+		return err
+	}
+	return d.DecodeReader(reader, dest)
+}
+
+// DecodeReader decodes the NDJSON body from a reader and appends each record to the destination slice. With the
+// ErrorPolicySkip policy, the valid records are appended and the line errors are returned joined
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any
+func (d Decoder) DecodeReader(
+	reader io.Reader,
+	dest any,
+) error {
+	return d.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes the NDJSON body from a reader and appends each record to the destination slice,
+// stopping once the context is done. The records decoded before are kept in the destination slice
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the body from
+//   - dest: The pointer to the slice to append the decoded records to
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context is joined to the line errors if it is done
+func (d Decoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
+) error {
+	// Check the reader
+	if reader == nil {
+		return gojsondecoder.ErrNilReader
+	}
+
+	// Check the decoder destination
+	if dest == nil {
+		return gojsondecoder.ErrNilDestination
+	}
+
+	// Check the destination is a pointer to a slice
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return gojsondecoder.ErrDestinationNotSlicePointer
+	}
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	// Decode each line into a new element
+	var lineErrors []error
+	err := d.scanLines(ctx, reader, func(line int, start gojsondecoder.Position, data []byte, scanErr error) bool {
+		if scanErr != nil {
+			lineErrors = append(lineErrors, newLineError(line, start, nil, scanErr))
+			return d.errorPolicy == ErrorPolicySkip
+		}
+
+		elemValue := reflect.New(elemType)
+		if decodeErr := gojsondecoder.DecodeIntoValue(d.recordDecoder, data, elemValue); decodeErr != nil {
+			lineErrors = append(lineErrors, newLineError(line, start, data, decodeErr))
+			return d.errorPolicy == ErrorPolicySkip
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elemValue.Elem()))
+		return true
+	})
+	if err != nil {
+		lineErrors = append(lineErrors, err)
+	}
+	return errors.Join(lineErrors...)
+}
+
+// ContentType returns the media type of the decoded body
+//
+// Returns:
+//
+//   - string: the media type
+func (d Decoder) ContentType() string {
+	return ContentType
+}
+
+// scanLines reads the reader line by line and calls the function with each non-empty line, or with the error found
+// while delimiting it, until the context is done. The lines larger than the maximum line size are discarded while
+// they are read and reported with ErrRecordTooLarge
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The reader to read the lines from
+//   - fn: The function to call with the line number, the position of the record, the record bytes and the
+//     delimiting error, it returns false to stop the scan. The bytes are only valid until the function returns
+//
+// Returns:
+//
+//   - error: The read error if any, the cause of the context if it is done
+func (d Decoder) scanLines(
+	ctx context.Context,
+	reader io.Reader,
+	fn func(line int, start gojsondecoder.Position, data []byte, scanErr error) bool,
+) error {
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	splitter := gojsonsequence.NewSplitter(contextReader, '\n', d.maxLineSize)
+
+	line := 0
+	for {
+		segment, err := splitter.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if ctxErr := context.Cause(ctx); ctxErr != nil {
+				return ctxErr
+			}
+			return NewLineError(line+1, err)
+		}
+
+		// Check the context
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		line++
+
+		// Report the oversized lines without stopping the scan
+		if segment.TooLarge {
+			if !fn(line, segment.Start, nil, gojsondecoder.ErrRecordTooLarge) {
+				return nil
+			}
+			continue
+		}
+
+		// Skip the empty lines
+		data := bytes.TrimSpace(segment.Data)
+		if len(data) == 0 {
+			continue
+		}
+
+		// Locate the record after the leading whitespace
+		leadingSpace := len(segment.Data) - len(bytes.TrimLeftFunc(segment.Data, unicode.IsSpace))
+		if !fn(line, segment.Start.Advance(segment.Data[:leadingSpace]), data, nil) {
+			return nil
+		}
+	}
+}
+
+// DecodeSeq returns a sequence that decodes the NDJSON body record by record, without reading the whole body
+//
+// A record that cannot be decoded is yielded with a *LineError. With the ErrorPolicyAbort policy the sequence stops
+// after it, with the ErrorPolicySkip policy it continues with the next line. The caller can also stop the sequence
+// at any time by breaking the loop. Read errors are always yielded last
+//
+// Parameters:
+//
+//   - decoder: The NDJSON decoder, nil uses the default decoder
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded records and errors
+func DecodeSeq[T any](
+	decoder *Decoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return DecodeSeqContext[T](context.Background(), decoder, reader)
+}
+
+// DecodeSeqContext returns a sequence that decodes the NDJSON body record by record, as DecodeSeq does, until the
+// context is done. The cause of the context is yielded last once it is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - decoder: The NDJSON decoder, nil uses the default decoder
+//   - reader: The reader to read the body from
+//
+// Returns:
+//
+//   - iter.Seq2[T, error]: The sequence of decoded records and errors
+func DecodeSeqContext[T any](
+	ctx context.Context,
+	decoder *Decoder,
+	reader io.Reader,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// Check the reader
+		if reader == nil {
+			yield(zero, gojsondecoder.ErrNilReader)
+			return
+		}
+
+		// Initialize the decoder
+		if decoder == nil {
+			decoder = NewDecoder(nil)
+		}
+
+		// Decode each line
+		err := decoder.scanLines(ctx, reader, func(
+			line int,
+			start gojsondecoder.Position,
+			data []byte,
+			scanErr error,
+		) bool {
+			if scanErr != nil {
+				lineErr := newLineError(line, start, nil, scanErr)
+				return yield(zero, lineErr) && decoder.errorPolicy == ErrorPolicySkip
+			}
+
+			var record T
+			if decodeErr := gojsondecoder.DecodeIntoValue(
+				decoder.recordDecoder,
+				data,
+				reflect.ValueOf(&record),
+			); decodeErr != nil {
+				lineErr := newLineError(line, start, data, decodeErr)
+				return yield(zero, lineErr) && decoder.errorPolicy == ErrorPolicySkip
+			}
+			return yield(record, nil)
+		})
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
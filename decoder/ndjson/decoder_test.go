@@ -0,0 +1,134 @@
+package ndjson
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type record struct {
+	ID int `json:"id"`
+}
+
+func TestDecoderDecodeReader(t *testing.T) {
+	oversized := `{"id":2,"padding":"` + strings.Repeat("x", 10000) + `"}`
+	tests := []struct {
+		name        string
+		input       string
+		errorPolicy ErrorPolicy
+		maxLineSize int
+		want        []int
+		wantLines   []int
+		wantErrs    []error
+	}{
+		{
+			name:  "decodes every line",
+			input: "{\"id\":1}\n{\"id\":2}\r\n\n{\"id\":3}",
+			want:  []int{1, 2, 3},
+		},
+		{
+			name:      "stops at the first malformed line",
+			input:     "{\"id\":1}\n{\"id\":\n{\"id\":3}\n",
+			want:      []int{1},
+			wantLines: []int{2},
+			wantErrs:  []error{nil},
+		},
+		{
+			name:        "skips the malformed lines",
+			input:       "{\"id\":1}\n{\"id\":\n{\"id\":3}\n",
+			errorPolicy: ErrorPolicySkip,
+			want:        []int{1, 3},
+			wantLines:   []int{2},
+			wantErrs:    []error{nil},
+		},
+		{
+			name:        "skips the oversized lines",
+			input:       "{\"id\":1}\n" + oversized + "\n{\"id\":3}\n",
+			errorPolicy: ErrorPolicySkip,
+			maxLineSize: 4096,
+			want:        []int{1, 3},
+			wantLines:   []int{2},
+			wantErrs:    []error{gojsondecoder.ErrRecordTooLarge},
+		},
+		{
+			name:        "stops at the oversized lines",
+			input:       "{\"id\":1}\n" + oversized + "\n{\"id\":3}\n",
+			maxLineSize: 4096,
+			want:        []int{1},
+			wantLines:   []int{2},
+			wantErrs:    []error{gojsondecoder.ErrRecordTooLarge},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewDecoder(NewOptions(nil, test.errorPolicy, test.maxLineSize))
+				var dest []record
+				err := decoder.DecodeReader(strings.NewReader(test.input), &dest)
+
+				// Check the decoded records
+				var got []int
+				for _, value := range dest {
+					got = append(got, value.ID)
+				}
+				if !slices.Equal(got, test.want) {
+					t.Errorf("decoded records = %v, want %v", got, test.want)
+				}
+
+				// Check the line errors
+				var lineErrs []*LineError
+				if err != nil {
+					for _, joinedErr := range err.(interface{ Unwrap() []error }).Unwrap() {
+						var lineErr *LineError
+						if !errors.As(joinedErr, &lineErr) {
+							t.Fatalf("error %v is not a *LineError", joinedErr)
+						}
+						lineErrs = append(lineErrs, lineErr)
+					}
+				}
+				if len(lineErrs) != len(test.wantLines) {
+					t.Fatalf("errors = %v, want %d line errors", err, len(test.wantLines))
+				}
+				for i, lineErr := range lineErrs {
+					if lineErr.Line != test.wantLines[i] {
+						t.Errorf("error %d line = %d, want %d", i, lineErr.Line, test.wantLines[i])
+					}
+					if test.wantErrs[i] != nil && !errors.Is(lineErr, test.wantErrs[i]) {
+						t.Errorf("error %d = %v, want %v", i, lineErr, test.wantErrs[i])
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestDecodeSeqContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the context once the second record is decoded, the next lines are not decoded
+	var got []int
+	var gotErr error
+	input := strings.NewReader("{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n{\"id\":4}\n")
+	for value, err := range DecodeSeqContext[record](ctx, nil, input) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, value.ID)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("records = %v, want %v", got, []int{1, 2})
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("error = %v, want %v", gotErr, context.Canceled)
+	}
+}
@@ -0,0 +1,74 @@
+package ndjson
+
+import (
+	"fmt"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+const (
+	ErrDecodeLine = "failed to decode record on line %d: %v"
+)
+
+type (
+	// LineError is the error returned when a record cannot be decoded, it carries the line number of the record
+	LineError struct {
+		Line int
+		Err  error
+	}
+)
+
+// NewLineError creates a new LineError instance
+//
+// Parameters:
+//
+//   - line: the line number of the record, starting at 1
+//   - err: the error returned while decoding the record
+//
+// Returns:
+//
+//   - *LineError: the new LineError instance
+func NewLineError(line int, err error) *LineError {
+	return &LineError{
+		Line: line,
+		Err:  err,
+	}
+}
+
+// newLineError creates the error returned when a record cannot be decoded, wrapping a *DecodeError located in the
+// body
+//
+// Parameters:
+//
+//   - line: the line number of the record, starting at 1
+//   - start: the position of the record in the body
+//   - data: the record bytes, nil if the line was not read
+//   - err: the error returned while decoding the record
+//
+// Returns:
+//
+//   - *LineError: the new LineError instance
+func newLineError(line int, start gojsondecoder.Position, data []byte, err error) *LineError {
+	if data == nil {
+		return NewLineError(line, gojsondecoder.NewDecodeError("", start, nil, err))
+	}
+	return NewLineError(line, gojsondecoder.NestDecodeError(data, err, "", start))
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: the error message
+func (l LineError) Error() string {
+	return fmt.Sprintf(ErrDecodeLine, l.Line, l.Err)
+}
+
+// Unwrap returns the wrapped error
+//
+// Returns:
+//
+//   - error: the wrapped error
+func (l LineError) Unwrap() error {
+	return l.Err
+}
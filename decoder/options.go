@@ -0,0 +1,154 @@
+package decoder
+
+import (
+	"bytes"
+)
+
+type (
+	// ErrorMode is how the decoders report the errors found in a document
+	ErrorMode int
+
+	// DuplicateKeys is the policy applied to the repeated member names of a JSON object
+	DuplicateKeys int
+
+	// StrictOptions are the strictness settings shared by the decoders, so the ambiguous documents are handled the
+	// same way whatever the decoder is
+	StrictOptions struct {
+		// disallowUnknownFields indicates whether the object members that do not match a field are rejected
+		disallowUnknownFields bool
+
+		// duplicateKeys is the policy applied to the repeated member names of an object
+		duplicateKeys DuplicateKeys
+
+		// disallowTrailingData indicates whether the data after the top-level value is rejected, otherwise it is
+		// ignored
+		disallowTrailingData bool
+
+		// disallowInvalidUTF8 indicates whether the invalid UTF-8 sequences are rejected, otherwise they are
+		// replaced with the Unicode replacement character
+		disallowInvalidUTF8 bool
+	}
+)
+
+const (
+	// ErrorModeFirst stops the decoding at the first error and returns it as a *DecodeError
+	ErrorModeFirst ErrorMode = iota
+
+	// ErrorModeCollect keeps decoding the valid members after an error, validates the destination if it implements
+	// Validator, and returns every error found as DecodeErrors
+	ErrorModeCollect
+)
+
+const (
+	// DuplicateKeysLastWins keeps the last member of the repeated names, as encoding/json does
+	DuplicateKeysLastWins DuplicateKeys = iota
+
+	// DuplicateKeysFirstWins keeps the first member of the repeated names
+	DuplicateKeysFirstWins
+
+	// DuplicateKeysError rejects the objects with repeated member names
+	DuplicateKeysError
+)
+
+// NewStrictOptions creates a new StrictOptions instance
+//
+// Parameters:
+//
+//   - disallowUnknownFields: whether the object members that do not match a field are rejected
+//   - duplicateKeys: the policy applied to the repeated member names of an object
+//   - disallowTrailingData: whether the data after the top-level value is rejected, otherwise it is ignored
+//   - disallowInvalidUTF8: whether the invalid UTF-8 sequences are rejected, otherwise they are replaced with the
+//     Unicode replacement character
+//
+// Returns:
+//
+//   - *StrictOptions: the new StrictOptions instance
+func NewStrictOptions(
+	disallowUnknownFields bool,
+	duplicateKeys DuplicateKeys,
+	disallowTrailingData bool,
+	disallowInvalidUTF8 bool,
+) *StrictOptions {
+	return &StrictOptions{
+		disallowUnknownFields: disallowUnknownFields,
+		duplicateKeys:         duplicateKeys,
+		disallowTrailingData:  disallowTrailingData,
+		disallowInvalidUTF8:   disallowInvalidUTF8,
+	}
+}
+
+// NewStrictestOptions creates a new StrictOptions instance that rejects every ambiguous document, for the
+// security-sensitive endpoints
+//
+// Returns:
+//
+//   - *StrictOptions: the new StrictOptions instance
+func NewStrictestOptions() *StrictOptions {
+	return NewStrictOptions(true, DuplicateKeysError, true, true)
+}
+
+// DisallowUnknownFields returns whether the object members that do not match a field are rejected
+//
+// Returns:
+//
+//   - bool: whether the unknown fields are rejected
+func (s *StrictOptions) DisallowUnknownFields() bool {
+	return s != nil && s.disallowUnknownFields
+}
+
+// Apply checks the document against the strict settings before it is decoded. It returns the document to decode,
+// which differs from the given one when the trailing data is ignored or the repeated members are resolved to the
+// first one. The repeated members are blanked out, so the positions in the returned document are the same as in
+// the original one. Nil strict settings return the document as is
+//
+// Parameters:
+//
+//   - data: the JSON document
+//   - errorMode: how the violations are reported, the first one as a *DecodeError or all of them as DecodeErrors
+//
+// Returns:
+//
+//   - []byte: the document to decode
+//   - error: the violations located in the document, nil if there are none. The syntax errors are not reported, so
+//     the decoder reports them
+func (s *StrictOptions) Apply(data []byte, errorMode ErrorMode) ([]byte, error) {
+	if s == nil {
+		return data, nil
+	}
+
+	// Check the encoding
+	var errs []error
+	if s.disallowInvalidUTF8 {
+		if offset := invalidUTF8Offset(data); offset >= 0 {
+			errs = append(errs, NewDecodeError("", PositionAt(data, offset), nil, ErrInvalidUTF8))
+		}
+	}
+
+	// Check the repeated members and the end of the top-level value
+	scanner := newStrictScanner(data, s.duplicateKeys)
+	if err := scanner.scanValue(""); err == nil {
+		errs = append(errs, scanner.errs...)
+
+		end := scanner.decoder.InputOffset()
+		if trailing := bytes.TrimLeft(data[end:], " \t\r\n"); len(trailing) > 0 {
+			if s.disallowTrailingData {
+				offset := int64(len(data) - len(trailing))
+				errs = append(errs, NewDecodeError("", PositionAt(data, offset), nil, ErrTrailingData))
+			} else {
+				data = data[:end]
+			}
+		}
+		if len(errs) == 0 {
+			data = blankSpans(data, scanner.duplicates)
+		}
+	}
+
+	// Report the violations
+	if len(errs) == 0 {
+		return data, nil
+	}
+	if errorMode == ErrorModeCollect {
+		return data, NewDecodeErrors(errs...)
+	}
+	return data, errs[0]
+}
@@ -0,0 +1,119 @@
+package decoder
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+type (
+	// Position is the location of a byte in a JSON document, the line and the column are counted in bytes starting
+	// at 1
+	Position struct {
+		Offset int64
+		Line   int
+		Column int
+	}
+)
+
+var (
+	// StartPosition is the position of the first byte of a document
+	StartPosition = Position{Line: 1, Column: 1}
+
+	// UnknownPosition is the position of an error whose location is not known
+	UnknownPosition = Position{Offset: -1}
+)
+
+// PositionAt returns the position of the byte at the given offset of the data
+//
+// Parameters:
+//
+//   - data: the document
+//   - offset: the byte offset
+//
+// Returns:
+//
+//   - Position: the position, without line and column if the offset is out of the data
+func PositionAt(data []byte, offset int64) Position {
+	if offset < 0 || offset > int64(len(data)) {
+		return Position{Offset: offset}
+	}
+	return StartPosition.Advance(data[:offset])
+}
+
+// positionOfLine returns the position of the byte at the given line and column of the data, the column being
+// counted in runes as protojson reports it. The returned column is counted in bytes, as the other positions
+//
+// Parameters:
+//
+//   - data: the document
+//   - line: the line, starting at 1
+//   - column: the column in runes, starting at 1
+//
+// Returns:
+//
+//   - Position: the position, with an unknown offset if the line is out of the data
+func positionOfLine(data []byte, line, column int) Position {
+	offset := 0
+	for currentLine := 1; currentLine < line; currentLine++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return Position{Offset: -1, Line: line, Column: column}
+		}
+		offset += i + 1
+	}
+
+	// Skip the runes before the column, without crossing the end of the line
+	for currentColumn := 1; currentColumn < column && offset < len(data) && data[offset] != '\n'; currentColumn++ {
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return PositionAt(data, int64(offset))
+}
+
+// Advance returns the position that follows the given bytes, read from this position
+//
+// Parameters:
+//
+//   - data: the bytes read from this position
+//
+// Returns:
+//
+//   - Position: the position after the bytes
+func (p Position) Advance(data []byte) Position {
+	p.Offset += int64(len(data))
+	if p.Line == 0 {
+		return p
+	}
+	if newLines := bytes.Count(data, []byte{'\n'}); newLines > 0 {
+		p.Line += newLines
+		p.Column = len(data) - bytes.LastIndexByte(data, '\n')
+	} else {
+		p.Column += len(data)
+	}
+	return p
+}
+
+// Nest returns the position in the enclosing document of a position relative to a value that starts at this
+// position
+//
+// Parameters:
+//
+//   - inner: the position relative to the value
+//
+// Returns:
+//
+//   - Position: the position relative to the enclosing document, the unknown parts are left unknown
+func (p Position) Nest(inner Position) Position {
+	nested := Position{Offset: -1}
+	if p.Offset >= 0 && inner.Offset >= 0 {
+		nested.Offset = p.Offset + inner.Offset
+	}
+	if p.Line > 0 && inner.Line > 0 {
+		nested.Line = p.Line + inner.Line - 1
+		nested.Column = inner.Column
+		if inner.Line == 1 {
+			nested.Column = p.Column + inner.Column - 1
+		}
+	}
+	return nested
+}
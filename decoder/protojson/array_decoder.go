@@ -0,0 +1,33 @@
+package protojson
+
+import (
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+)
+
+// NewArrayDecoder creates a new JSON array decoder whose elements are decoded with the given decoder, through its
+// mapper for the elements with proto.Message fields, so they can be decoded with DecodeReader and
+// gojsondecoderjson.DecodeArraySeq
+//
+// Parameters:
+//
+//   - decoder: the decoder used to decode each element, nil uses the default decoder
+//   - maxElementSize: the maximum size in bytes of an element, checked while the element is read, 0 means no limit
+//   - maxElements: the maximum number of elements of the array, 0 means no limit
+//
+// Returns:
+//
+//   - *gojsondecoderjson.ArrayDecoder: the new ArrayDecoder instance
+func NewArrayDecoder(
+	decoder *Decoder,
+	maxElementSize int64,
+	maxElements int,
+) *gojsondecoderjson.ArrayDecoder {
+	// Initialize the decoder
+	if decoder == nil {
+		decoder = NewDecoder(nil)
+	}
+
+	return gojsondecoderjson.NewArrayDecoder(
+		gojsondecoderjson.NewArrayOptions(decoder, maxElementSize, maxElements),
+	)
+}
@@ -0,0 +1,87 @@
+package protojson
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+)
+
+func TestNewArrayDecoder(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		maxElementSize int64
+		want           []string
+		wantPath       string
+	}{
+		{
+			name:  "decodes the proto.Message fields of the elements",
+			input: `[{"name":"a","file":{"name":"a.proto","messageType":[{"name":"A"}]}},{"name":"b"}]`,
+			want:  []string{"a.proto", ""},
+		},
+		{
+			name:     "locates the invalid proto.Message fields in the array",
+			input:    `[{"name":"a","file":{"name":"a.proto"}},{"name":"b","file":{"name":1}}]`,
+			want:     []string{"a.proto"},
+			wantPath: "/1/file",
+		},
+		{
+			name:           "rejects the oversized elements",
+			input:          `[{"name":"a","file":{"name":"` + strings.Repeat("x", 64) + `.proto"}}]`,
+			maxElementSize: 32,
+			wantPath:       "/0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewArrayDecoder(nil, test.maxElementSize, 0)
+				var dest []strictBody
+				err := decoder.DecodeReader(strings.NewReader(test.input), &dest)
+
+				// Check the decoded elements
+				var got []string
+				for _, element := range dest {
+					got = append(got, element.File.GetName())
+				}
+				if !slices.Equal(got, test.want) {
+					t.Errorf("files = %q, want %q", got, test.want)
+				}
+
+				// Check the location of the error
+				if test.wantPath == "" {
+					if err != nil {
+						t.Fatalf("error = %v, want nil", err)
+					}
+					return
+				}
+				var decodeErr *gojsondecoder.DecodeError
+				if !errors.As(err, &decodeErr) || decodeErr.Path != test.wantPath {
+					t.Errorf("error = %v, want a *DecodeError at %s", err, test.wantPath)
+				}
+			},
+		)
+	}
+}
+
+func TestNewArrayDecoderSeq(t *testing.T) {
+	input := `[{"name":"a","file":{"name":"a.proto"}},{"name":"b","file":{"syntax":"proto3"}}]`
+	var got []string
+	for element, err := range gojsondecoderjson.DecodeArraySeq[strictBody](
+		NewArrayDecoder(nil, 0, 0),
+		strings.NewReader(input),
+	) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, element.File.GetName()+element.File.GetSyntax())
+	}
+	if !slices.Equal(got, []string{"a.proto", "proto3"}) {
+		t.Errorf("files = %q, want [\"a.proto\" \"proto3\"]", got)
+	}
+}
@@ -0,0 +1,115 @@
+package protojson
+
+import (
+	"errors"
+	"reflect"
+
+	gojsoncache "github.com/ralvarezdev/go-json/internal/cache"
+)
+
+type (
+	// MapperCache is the concurrency-safe cache of the mappers by destination type, it can be shared by several
+	// decoders. Once it holds its maximum size the least recently used mapper is evicted
+	MapperCache struct {
+		cache *gojsoncache.Cache[reflect.Type, *Mapper]
+	}
+
+	// CacheStats are the statistics of a MapperCache
+	CacheStats = gojsoncache.Stats
+)
+
+// NewMapperCache creates a new MapperCache instance
+//
+// Parameters:
+//
+//   - maxSize: the maximum number of cached mappers, 0 means no limit
+//
+// Returns:
+//
+//   - *MapperCache: the new MapperCache instance
+func NewMapperCache(maxSize int) *MapperCache {
+	return &MapperCache{
+		cache: gojsoncache.New[reflect.Type, *Mapper](maxSize),
+	}
+}
+
+// Mapper returns the mapper of the destination type, creating and caching it if it is not cached
+//
+// Parameters:
+//
+//   - reflectType: the proto.Message type, the struct type or the pointer to the struct type
+//
+// Returns:
+//
+//   - *Mapper: the mapper of the destination type
+//   - error: the error if the type is nil or is not a struct
+func (m *MapperCache) Mapper(reflectType reflect.Type) (*Mapper, error) {
+	// Check if the type is nil
+	if reflectType == nil {
+		return nil, ErrNilType
+	}
+
+	// The mapper is created from the pointer type so the proto.Message types are detected
+	key := mapperKey(reflectType)
+	return m.cache.GetOrAdd(
+		key, func() (*Mapper, error) {
+			return NewMapperForType(reflect.PointerTo(key))
+		},
+	)
+}
+
+// Warm creates and caches the mappers of the destination types, so they are not created while decoding. The lookups
+// are not counted in the statistics
+//
+// Parameters:
+//
+//   - types: the proto.Message types, the struct types or the pointers to the struct types
+//
+// Returns:
+//
+//   - error: the errors of the types whose mapper cannot be created, the other types are cached
+func (m *MapperCache) Warm(types ...reflect.Type) error {
+	var errs []error
+	for _, reflectType := range types {
+		// Check if the type is nil
+		if reflectType == nil {
+			errs = append(errs, ErrNilType)
+			continue
+		}
+
+		key := mapperKey(reflectType)
+		mapper, err := NewMapperForType(reflect.PointerTo(key))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.cache.Add(key, mapper)
+	}
+	return errors.Join(errs...)
+}
+
+// mapperKey returns the type the mapper of the destination type is cached by, the struct type and its pointer type
+// share the same mapper
+//
+// Parameters:
+//
+//   - reflectType: the destination type
+//
+// Returns:
+//
+//   - reflect.Type: the dereferenced type
+func mapperKey(reflectType reflect.Type) reflect.Type {
+	if reflectType.Kind() == reflect.Ptr {
+		return reflectType.Elem()
+	}
+	return reflectType
+}
+
+// Stats returns the statistics of the cache
+//
+// Returns:
+//
+//   - CacheStats: the statistics
+func (m *MapperCache) Stats() CacheStats {
+	return m.cache.Stats()
+}
@@ -0,0 +1,484 @@
+package protojson
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type (
+	// collectionMapper is the mapper of a slice, an array or a map whose elements are proto.Message, structs with
+	// proto.Message fields, or nested collections of them
+	collectionMapper struct {
+		reflectType        reflect.Type
+		elemType           reflect.Type
+		elemIsProtoMessage bool
+		elemMapper         *Mapper
+		elemCollection     *collectionMapper
+	}
+)
+
+var (
+	// protoMessageType is the reflect type of the proto.Message interface
+	protoMessageType = reflect.TypeFor[proto.Message]()
+
+	// textUnmarshalerType is the reflect type of the encoding.TextUnmarshaler interface
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	// nullLiteral is the JSON null literal
+	nullLiteral = []byte("null")
+)
+
+// newCollectionMapper creates a new collectionMapper instance if the type is a collection of proto.Message, of
+// structs with proto.Message fields, or of nested collections of them
+//
+// Parameters:
+//
+//   - reflectType: the type of the collection
+//   - building: the mappers of the struct types that are being built, by type
+//
+// Returns:
+//
+//   - *collectionMapper: the new collectionMapper instance, nil if the type is not such a collection, so it is
+//     decoded as a regular field
+//   - error: the error if the mapper of the struct elements cannot be created
+func newCollectionMapper(
+	reflectType reflect.Type,
+	building map[reflect.Type]*Mapper,
+) (*collectionMapper, error) {
+	// Check the kind of the collection and the type of the map keys
+	switch reflectType.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Map:
+		if !isMapKeySupported(reflectType.Key()) {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	// Check the elements, the proto.Message are only handled through their pointer type
+	elemType := reflectType.Elem()
+	collection := &collectionMapper{
+		reflectType: reflectType,
+		elemType:    elemType,
+	}
+	if elemType.Kind() == reflect.Ptr && elemType.Implements(protoMessageType) {
+		collection.elemIsProtoMessage = true
+		return collection, nil
+	}
+
+	// Check if the elements are structs with proto.Message fields, or pointers to them
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() == reflect.Struct {
+		// The struct values of proto.Message are left to encoding/json
+		if reflect.PointerTo(structType).Implements(protoMessageType) {
+			return nil, nil
+		}
+
+		// The structs without proto.Message fields are left to encoding/json, they are checked from their type alone
+		if !hasProtoMessageFields(structType, make(map[reflect.Type]struct{})) {
+			return nil, nil
+		}
+
+		// Reuse the mapper of the struct type if it is already being built, it is a recursive type
+		if elemMapper, ok := building[structType]; ok {
+			collection.elemMapper = elemMapper
+			return collection, nil
+		}
+		elemMapper, err := newMapper(structType, building)
+		if err != nil {
+			return nil, err
+		}
+		collection.elemMapper = elemMapper
+		return collection, nil
+	}
+
+	// Check if the elements are nested collections
+	elemCollection, err := newCollectionMapper(elemType, building)
+	if err != nil || elemCollection == nil {
+		return nil, err
+	}
+	collection.elemCollection = elemCollection
+	return collection, nil
+}
+
+// isProtoMessageCollection returns whether the type is a slice, an array or a map whose elements are proto.Message,
+// structs with proto.Message fields, or nested collections of them, as newCollectionMapper classifies them
+//
+// Parameters:
+//
+//   - reflectType: the type to check
+//   - visited: the struct types already checked, the recursive types are checked once
+//
+// Returns:
+//
+//   - bool: whether the type is such a collection
+func isProtoMessageCollection(reflectType reflect.Type, visited map[reflect.Type]struct{}) bool {
+	// Check the kind of the collection and the type of the map keys
+	switch reflectType.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Map:
+		if !isMapKeySupported(reflectType.Key()) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	// Check the elements, the proto.Message are only handled through their pointer type
+	elemType := reflectType.Elem()
+	if elemType.Kind() == reflect.Ptr && elemType.Implements(protoMessageType) {
+		return true
+	}
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() == reflect.Struct {
+		return !reflect.PointerTo(structType).Implements(protoMessageType) &&
+			hasProtoMessageFields(structType, visited)
+	}
+	return isProtoMessageCollection(elemType, visited)
+}
+
+// isMapKeySupported returns whether the map key type can be decoded from a JSON object member name, as encoding/json
+// does
+//
+// Parameters:
+//
+//   - keyType: the type of the map keys
+//
+// Returns:
+//
+//   - bool: whether the key type is a string, an integer or implements encoding.TextUnmarshaler
+func isMapKeySupported(keyType reflect.Type) bool {
+	if reflect.PointerTo(keyType).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch keyType.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// unmarshal unmarshal a JSON array or object into the collection, each element is unmarshal on its own so the
+// errors are reported per index or key
+//
+// Parameters:
+//
+//   - ctx: The context, it is checked before each element
+//   - data: The JSON array or object
+//   - value: The settable collection value
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid elements after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, a *DecodeError or DecodeErrors relative to the data for the elements that cannot be
+//     decoded
+func (c collectionMapper) unmarshal(
+	ctx context.Context,
+	data []byte,
+	value reflect.Value,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	// The null collection is left empty, as encoding/json does
+	if bytes.Equal(data, nullLiteral) {
+		value.SetZero()
+		return nil
+	}
+
+	if c.reflectType.Kind() == reflect.Map {
+		return c.unmarshalMap(ctx, data, value, unmarshalOptions, collect)
+	}
+	return c.unmarshalList(ctx, data, value, unmarshalOptions, collect)
+}
+
+// unmarshalList unmarshal a JSON array into the slice or the array, the extra elements are ignored and the missing
+// ones are left zero for the arrays, as encoding/json does
+//
+// Parameters:
+//
+//   - ctx: The context, it is checked before each element
+//   - data: The JSON array
+//   - value: The settable slice or array value
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid elements after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, relative to the data
+func (c collectionMapper) unmarshalList(
+	ctx context.Context,
+	data []byte,
+	value reflect.Value,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	// Split the array into its elements
+	elements, offsets, err := arrayElements(data)
+	if err != nil {
+		return err
+	}
+
+	// Create the slice, or reset the array
+	list := value
+	if c.reflectType.Kind() == reflect.Slice {
+		list = reflect.MakeSlice(c.reflectType, len(elements), len(elements))
+	} else {
+		value.SetZero()
+	}
+
+	// Unmarshal each element
+	var elemErrs []error
+	for i, element := range elements[:min(len(elements), list.Len())] {
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		if elemErr := c.unmarshalElement(ctx, element, list.Index(i), unmarshalOptions, collect); elemErr != nil {
+			elemErr = newNestedDecodeError(element, strconv.Itoa(i), offsets[i], c.elemType, elemErr)
+			if !collect {
+				return elemErr
+			}
+			elemErrs = append(elemErrs, elemErr)
+		}
+	}
+	value.Set(list)
+	return gojsondecoder.NewDecodeErrors(elemErrs...)
+}
+
+// unmarshalMap unmarshal a JSON object into the map, the members are added to the existing map, as encoding/json
+// does
+//
+// Parameters:
+//
+//   - ctx: The context, it is checked before each member
+//   - data: The JSON object
+//   - value: The settable map value
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid members after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, relative to the data
+func (c collectionMapper) unmarshalMap(
+	ctx context.Context,
+	data []byte,
+	value reflect.Value,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	// Split the object into its members
+	keys, elements, offsets, err := objectMembers(data)
+	if err != nil {
+		return err
+	}
+
+	// Create the map if it is nil
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(c.reflectType, len(keys)))
+	}
+
+	// Unmarshal each member
+	var elemErrs []error
+	for i, key := range keys {
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+
+		// Convert the member name into the key, then unmarshal its value
+		keyValue, elemErr := c.mapKey(key)
+		expected := c.reflectType.Key()
+		elemValue := reflect.New(c.elemType).Elem()
+		if elemErr == nil {
+			expected = c.elemType
+			elemErr = c.unmarshalElement(ctx, elements[i], elemValue, unmarshalOptions, collect)
+		}
+		if elemErr != nil {
+			elemErr = newNestedDecodeError(elements[i], key, offsets[i], expected, elemErr)
+			if !collect {
+				return elemErr
+			}
+			elemErrs = append(elemErrs, elemErr)
+			continue
+		}
+		value.SetMapIndex(keyValue, elemValue)
+	}
+	return gojsondecoder.NewDecodeErrors(elemErrs...)
+}
+
+// mapKey converts the member name of a JSON object into a key of the map
+//
+// Parameters:
+//
+//   - key: The member name
+//
+// Returns:
+//
+//   - reflect.Value: The key value
+//   - error: The error if the member name cannot be converted
+func (c collectionMapper) mapKey(key string) (reflect.Value, error) {
+	keyType := c.reflectType.Key()
+
+	// The encoding.TextUnmarshaler keys take precedence, as encoding/json does
+	if reflect.PointerTo(keyType).Implements(textUnmarshalerType) {
+		keyValue := reflect.New(keyType)
+		if err := keyValue.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, err
+		}
+		return keyValue.Elem(), nil
+	}
+
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(keyType), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf(ErrInvalidMapKey, key, keyType)
+		}
+		return reflect.ValueOf(number).Convert(keyType), nil
+	default:
+		number, err := strconv.ParseUint(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf(ErrInvalidMapKey, key, keyType)
+		}
+		return reflect.ValueOf(number).Convert(keyType), nil
+	}
+}
+
+// unmarshalElement unmarshal an element of the collection, with the proto unmarshal options, the mapper of its
+// struct type, or as a nested collection
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - data: The raw element
+//   - elemValue: The settable element value
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid fields after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, relative to the element
+func (c collectionMapper) unmarshalElement(
+	ctx context.Context,
+	data []byte,
+	elemValue reflect.Value,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	if c.elemCollection != nil {
+		return c.elemCollection.unmarshal(ctx, data, elemValue, unmarshalOptions, collect)
+	}
+
+	// The null pointer elements are left nil
+	isPointer := c.elemType.Kind() == reflect.Ptr
+	if isPointer && bytes.Equal(data, nullLiteral) {
+		elemValue.SetZero()
+		return nil
+	}
+
+	// Allocate the pointed element, the element is unmarshal through its pointer
+	dest := elemValue
+	if isPointer {
+		elemValue.Set(reflect.New(c.elemType.Elem()))
+	} else {
+		dest = elemValue.Addr()
+	}
+
+	if c.elemIsProtoMessage {
+		protoMessage, _ := dest.Interface().(proto.Message)
+		return unmarshalOptions.Unmarshal(data, protoMessage)
+	}
+	return c.elemMapper.unmarshal(ctx, data, dest.Interface(), unmarshalOptions, collect)
+}
+
+// arrayElements splits a JSON array into its raw elements
+//
+// Parameters:
+//
+//   - data: The JSON array
+//
+// Returns:
+//
+//   - []json.RawMessage: The raw elements
+//   - []int64: The offset of each element in the array
+//   - error: The error if the data is not a JSON array
+func arrayElements(data []byte) ([]json.RawMessage, []int64, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, nil, ErrExpectedArray
+	}
+
+	var elements []json.RawMessage
+	var offsets []int64
+	for decoder.More() {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return nil, nil, err
+		}
+		elements = append(elements, element)
+		offsets = append(offsets, decoder.InputOffset()-int64(len(element)))
+	}
+	return elements, offsets, nil
+}
+
+// objectMembers splits a JSON object into its member names and raw values
+//
+// Parameters:
+//
+//   - data: The JSON object
+//
+// Returns:
+//
+//   - []string: The member names
+//   - []json.RawMessage: The raw values
+//   - []int64: The offset of each value in the object
+//   - error: The error if the data is not a JSON object
+func objectMembers(data []byte) ([]string, []json.RawMessage, []int64, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, nil, ErrExpectedObject
+	}
+
+	var keys []string
+	var values []json.RawMessage
+	var offsets []int64
+	for decoder.More() {
+		// Read the member name
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		key, _ := token.(string)
+
+		// Read the member value
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil, nil, nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		offsets = append(offsets, decoder.InputOffset()-int64(len(value)))
+	}
+	return keys, values, offsets, nil
+}
@@ -1,27 +1,48 @@
 package protojson
 
 import (
+	"context"
+	"encoding/json"
 	"io"
+	"reflect"
 
 	"google.golang.org/protobuf/encoding/protojson"
 
-	goreflect "github.com/ralvarezdev/go-reflect"
-
 	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
 )
 
 type (
 	Decoder struct {
 		unmarshalOptions protojson.UnmarshalOptions
-		cache            bool
-		cachedMappers    map[string]*Mapper
+		mapperCache      *MapperCache
+		errorMode        gojsondecoder.ErrorMode
+		strictOptions    *gojsondecoder.StrictOptions
+		limits           *gojsondecoder.Limits
+		mapper           *Mapper
 	}
 
 	// Options are the additional settings for the decoder implementation
 	Options struct {
 		// cache indicates whether to cache the precompute unmarshal by reflection functions
 		cache bool
+
+		// errorMode is how the errors found in a document are reported
+		errorMode gojsondecoder.ErrorMode
+
+		// strictOptions are the strictness settings applied to the documents
+		strictOptions *gojsondecoder.StrictOptions
+
+		// limits are the resource limits applied to the documents while they are read
+		limits *gojsondecoder.Limits
+
+		// mapperCache is the cache of the mappers, it can be shared by several decoders
+		mapperCache *MapperCache
 	}
+
+	// Option sets an optional setting of the Options
+	Option func(options *Options)
 )
 
 // NewOptions creates a new Options instance
@@ -29,15 +50,85 @@ type (
 // Parameters:
 //
 //   - cache: indicates whether to cache the precompute unmarshal by reflection functions
+//   - opts: the optional settings, such as WithErrorMode, WithStrictOptions, WithLimits and WithMapperCache
 //
 // Returns:
 //
 // - *Options: the new Options instance
 func NewOptions(
 	cache bool,
+	opts ...Option,
 ) *Options {
-	return &Options{
-		cache: cache,
+	options := &Options{
+		cache:     cache,
+		errorMode: gojsondecoder.ErrorModeFirst,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithErrorMode sets how the errors found in a document are reported, ErrorModeFirst by default
+//
+// Parameters:
+//
+//   - errorMode: how the errors found in a document are reported
+//
+// Returns:
+//
+//   - Option: the option
+func WithErrorMode(errorMode gojsondecoder.ErrorMode) Option {
+	return func(options *Options) {
+		options.errorMode = errorMode
+	}
+}
+
+// WithStrictOptions sets the strictness settings applied to the documents, the unknown fields are discarded without
+// them
+//
+// Parameters:
+//
+//   - strictOptions: the strictness settings
+//
+// Returns:
+//
+//   - Option: the option
+func WithStrictOptions(strictOptions *gojsondecoder.StrictOptions) Option {
+	return func(options *Options) {
+		options.strictOptions = strictOptions
+	}
+}
+
+// WithLimits sets the resource limits applied to the documents while they are read, there are no limits without
+// them
+//
+// Parameters:
+//
+//   - limits: the resource limits
+//
+// Returns:
+//
+//   - Option: the option
+func WithLimits(limits *gojsondecoder.Limits) Option {
+	return func(options *Options) {
+		options.limits = limits
+	}
+}
+
+// WithMapperCache sets the cache of the mappers used if the cache is enabled, it can be shared by several decoders.
+// Without it, an unbounded cache is created for the decoder
+//
+// Parameters:
+//
+//   - mapperCache: the cache of the mappers
+//
+// Returns:
+//
+//   - Option: the option
+func WithMapperCache(mapperCache *MapperCache) Option {
+	return func(options *Options) {
+		options.mapperCache = mapperCache
 	}
 }
 
@@ -51,24 +142,55 @@ func NewOptions(
 //
 //   - *Decoder: The decoder instance
 func NewDecoder(options *Options) *Decoder {
-	// Initialize cache setting
-	cache := false
+	// Initialize cache, error mode, strictness and limits settings
+	var mapperCache *MapperCache
+	errorMode := gojsondecoder.ErrorModeFirst
+	var strictOptions *gojsondecoder.StrictOptions
+	var limits *gojsondecoder.Limits
 	if options != nil {
-		cache = options.cache
+		errorMode = options.errorMode
+		strictOptions = options.strictOptions
+		limits = options.limits
 	}
 
-	// Initialize unmarshal options
+	// Initialize the mapper cache, it is shared by the copies of the decoder
+	if options != nil && options.cache {
+		mapperCache = options.mapperCache
+		if mapperCache == nil {
+			mapperCache = NewMapperCache(0)
+		}
+	}
+
+	// Initialize unmarshal options, the unknown fields are discarded unless the strictness settings disallow them
 	unmarshalOptions := protojson.UnmarshalOptions{
-		DiscardUnknown: true,
+		DiscardUnknown: !strictOptions.DisallowUnknownFields(),
 		AllowPartial:   true,
 	}
 
 	return &Decoder{
 		unmarshalOptions: unmarshalOptions,
-		cache:            cache,
+		mapperCache:      mapperCache,
+		errorMode:        errorMode,
+		strictOptions:    strictOptions,
+		limits:           limits,
 	}
 }
 
+// WithMapper returns a copy of the decoder that unmarshal the destinations of the mapper type with the given mapper,
+// without looking it up. The destinations of other types are still unmarshal with their own mapper
+//
+// Parameters:
+//
+//   - mapper: the mapper built for the type of the destinations
+//
+// Returns:
+//
+//   - *Decoder: the copy of the decoder
+func (d Decoder) WithMapper(mapper *Mapper) *Decoder {
+	d.mapper = mapper
+	return &d
+}
+
 // Decode decodes the JSON body from an any value and stores it in the destination
 //
 // Parameters:
@@ -110,6 +232,25 @@ func (d Decoder) Decode(
 func (d Decoder) DecodeReader(
 	reader io.Reader,
 	dest any,
+) error {
+	return d.DecodeReaderContext(context.Background(), reader, dest)
+}
+
+// DecodeReaderContext decodes a JSON body from a reader into a destination, stopping once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - reader: The io.Reader to read the body from
+//   - dest: The destination to decode the body into
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (d Decoder) DecodeReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	dest any,
 ) error {
 	// Check the reader
 	if reader == nil {
@@ -121,48 +262,94 @@ func (d Decoder) DecodeReader(
 		return gojsondecoder.ErrNilDestination
 	}
 
-	// Read all body from the reader
-	body, err := io.ReadAll(reader)
-	if err != nil {
+	// Read all body from the reader into a pooled buffer, checking the context and the limits while it is read
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	if _, err := buffer.ReadFrom(d.limits.Reader(contextReader)); err != nil {
 		return err
 	}
 
-	// Check if the cache is enabled and use cached mapper if available
-	if d.cache && d.cachedMappers != nil {
-		// Get the unique type identifier for the destination
-		uniqueTypeReference := goreflect.UniqueTypeReference(dest)
-
-		// Check if there is a cached mapper for the destination type
-		if mapper, found := d.cachedMappers[uniqueTypeReference]; found {
-			return mapper.UnmarshalByReflection(
-				body,
-				dest,
-				&d.unmarshalOptions,
-			)
-		}
+	// Check the context before decoding the body
+	if err := context.Cause(ctx); err != nil {
+		return err
 	}
 
-	// Initialize the cache map if caching is enabled
-	if d.cache && d.cachedMappers == nil {
-		d.cachedMappers = make(map[string]*Mapper)
+	// Check the body against the strictness settings
+	body, err := d.strictOptions.Apply(buffer.Bytes(), d.errorMode)
+	if err != nil {
+		return err
 	}
 
-	// Create a new mapper for the destination type
-	mapper, err := NewMapper(dest)
+	// Get the mapper of the destination type
+	mapper, err := d.lookupMapper(dest)
 	if err != nil {
 		return err
 	}
 
-	// Store the mapper in the cache if caching is enabled
-	if d.cache {
-		uniqueTypeReference := goreflect.UniqueTypeReference(dest)
-		d.cachedMappers[uniqueTypeReference] = mapper
+	// Unmarshal the body into the destination using the mapper
+	return d.unmarshal(ctx, mapper, body, dest)
+}
+
+// lookupMapper returns the mapper of the destination type, the mapper of the decoder if the destination is of its
+// type, or the cached one if the cache is enabled
+//
+// Parameters:
+//
+//   - dest: The destination to get the mapper for
+//
+// Returns:
+//
+//   - *Mapper: The mapper
+//   - error: The error if any
+func (d Decoder) lookupMapper(dest any) (*Mapper, error) {
+	if d.mapper.matches(dest) {
+		return d.mapper, nil
+	}
+	if d.mapperCache != nil {
+		return d.mapperCache.Mapper(reflect.TypeOf(dest))
+	}
+	return NewMapper(dest)
+}
+
+// unmarshal unmarshal the body into the destination using the mapper, according to the error mode, until the
+// context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - mapper: The mapper of the destination type
+//   - body: The body to unmarshal
+//   - dest: The destination to unmarshal the body into
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (d Decoder) unmarshal(
+	ctx context.Context,
+	mapper *Mapper,
+	body []byte,
+	dest any,
+) error {
+	// The errors cannot be collected if the body is not valid JSON
+	if d.errorMode != gojsondecoder.ErrorModeCollect || !json.Valid(body) {
+		return mapper.unmarshal(
+			ctx,
+			body,
+			dest,
+			&d.unmarshalOptions,
+			false,
+		)
 	}
 
-	// Unmarshal the body into the destination using the mapper
-	return mapper.UnmarshalByReflection(
+	// Collect the errors of every field, the destination is not validated if the walk was interrupted
+	err := mapper.unmarshal(ctx, body, dest, &d.unmarshalOptions, true)
+	if ctxErr := context.Cause(ctx); ctxErr != nil {
+		return ctxErr
+	}
+	return gojsondecoder.ToDecodeError(
 		body,
-		dest,
-		&d.unmarshalOptions,
+		gojsondecoder.NewDecodeErrors(err, gojsondecoder.Validate(dest)),
 	)
 }
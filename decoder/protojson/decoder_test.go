@@ -0,0 +1,292 @@
+package protojson
+
+import (
+	"errors"
+	"image"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type strictBody struct {
+	Name string                            `json:"name"`
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+func TestDecoderStrictOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		strictOptions *gojsondecoder.StrictOptions
+		body          string
+		wantName      string
+		wantFileName  string
+		wantErr       error
+	}{
+		{
+			name:          "keeps the last duplicate key",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          `{"name":"a","file":{"name":"x","name":"y"},"name":"b"}`,
+			wantName:      "b",
+			wantFileName:  "y",
+		},
+		{
+			name:          "keeps the first duplicate key",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysFirstWins, false, false),
+			body:          `{"name":"a","file":{"name":"x","name":"y"},"name":"b"}`,
+			wantName:      "a",
+			wantFileName:  "x",
+		},
+		{
+			name:          "rejects the duplicate keys of the proto messages",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysError, false, false),
+			body:          `{"name":"a","file":{"name":"x","name":"y"}}`,
+			wantErr:       gojsondecoder.ErrDuplicateKey,
+		},
+		{
+			name:     "discards the unknown fields by default",
+			body:     `{"name":"a","file":{"unknown":1},"unknown":1}`,
+			wantName: "a",
+		},
+		{
+			name:          "rejects the unknown fields of the proto messages",
+			strictOptions: gojsondecoder.NewStrictOptions(true, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          `{"name":"a","file":{"unknown":1}}`,
+			wantErr:       gojsondecoder.ErrUnknownField,
+		},
+		{
+			name:          "rejects the unknown fields of the structs",
+			strictOptions: gojsondecoder.NewStrictOptions(true, gojsondecoder.DuplicateKeysLastWins, false, false),
+			body:          `{"name":"a","unknown":1}`,
+			wantErr:       gojsondecoder.ErrUnknownField,
+		},
+		{
+			name:          "rejects the trailing data",
+			strictOptions: gojsondecoder.NewStrictOptions(false, gojsondecoder.DuplicateKeysLastWins, true, false),
+			body:          `{"name":"a"} {"name":"b"}`,
+			wantErr:       gojsondecoder.ErrTrailingData,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				decoder := NewDecoder(NewOptions(false, WithStrictOptions(test.strictOptions)))
+				var dest strictBody
+				err := decoder.DecodeReader(strings.NewReader(test.body), &dest)
+				if test.wantErr != nil {
+					if !errors.Is(err, test.wantErr) {
+						t.Errorf("error = %v, want %v", err, test.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				if dest.Name != test.wantName || dest.File.GetName() != test.wantFileName {
+					t.Errorf(
+						"decoded = %q, %q, want %q, %q",
+						dest.Name,
+						dest.File.GetName(),
+						test.wantName,
+						test.wantFileName,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	decoder := NewDecoder(NewOptions(false, WithLimits(gojsondecoder.NewLimits(0, 0, 0, 0, 4))))
+
+	// The limits apply to the proto message fields too
+	var dest strictBody
+	err := decoder.DecodeReader(strings.NewReader(`{"name":"a","file":{"name":"12345"}}`), &dest)
+	var limitErr *gojsondecoder.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != gojsondecoder.LimitStringLength {
+		t.Fatalf("error = %v, want a *LimitError for the string length", err)
+	}
+
+	// The documents within the limits are decoded
+	if err = decoder.DecodeReader(strings.NewReader(`{"name":"a","file":{"name":"1234"}}`), &dest); err != nil {
+		t.Fatalf("error = %v, want nil", err)
+	}
+	if dest.File.GetName() != "1234" {
+		t.Errorf("file name = %q, want \"1234\"", dest.File.GetName())
+	}
+}
+
+type nestedMessage struct {
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+type nestedBody struct {
+	Time          time.Time      `json:"time"`
+	TimePointer   *time.Time     `json:"time_pointer"`
+	Nested        nestedMessage  `json:"nested"`
+	NestedPointer *nestedMessage `json:"nested_pointer"`
+	Point         image.Point    `json:"point"`
+	PointPointer  *image.Point   `json:"point_pointer"`
+}
+
+func TestDecoderNestedStructs(t *testing.T) {
+	body := `{"time":"2026-10-17T12:00:00Z","time_pointer":"2026-10-17T12:00:00Z",` +
+		`"nested":{"file":{"name":"a.proto"}},"nested_pointer":{"file":{"name":"b.proto"}},` +
+		`"point":{"X":1,"Y":2},"point_pointer":{"X":3,"Y":4}}`
+	var dest nestedBody
+	if err := NewDecoder(nil).DecodeReader(strings.NewReader(body), &dest); err != nil {
+		t.Fatal(err)
+	}
+
+	// The structs without proto.Message fields are decoded as encoding/json does, by value or by pointer
+	want := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	if !dest.Time.Equal(want) || dest.TimePointer == nil || !dest.TimePointer.Equal(want) {
+		t.Errorf("times = %v, %v, want %v", dest.Time, dest.TimePointer, want)
+	}
+
+	// The structs without JSON tags are decoded as encoding/json does too
+	if dest.Point != image.Pt(1, 2) || dest.PointPointer == nil || *dest.PointPointer != image.Pt(3, 4) {
+		t.Errorf("points = %v, %v, want (1,2), (3,4)", dest.Point, dest.PointPointer)
+	}
+
+	// The proto.Message fields of the nested structs are decoded with protojson
+	if dest.Nested.File.GetName() != "a.proto" || dest.NestedPointer.File.GetName() != "b.proto" {
+		t.Errorf(
+			"files = %q, %q, want \"a.proto\", \"b.proto\"",
+			dest.Nested.File.GetName(),
+			dest.NestedPointer.File.GetName(),
+		)
+	}
+}
+
+type collectionBody struct {
+	Files   []*descriptorpb.FileDescriptorProto            `json:"files"`
+	Array   [2]*descriptorpb.FileDescriptorProto           `json:"array"`
+	ByName  map[string]*descriptorpb.FileDescriptorProto   `json:"by_name"`
+	ByID    map[int]*descriptorpb.FileDescriptorProto      `json:"by_id"`
+	Nested  []nestedMessage                                `json:"nested"`
+	Grouped map[string][]*descriptorpb.FileDescriptorProto `json:"grouped"`
+}
+
+// fileNames returns the names of the files in the collections of the body, the maps in ascending key order
+func (c collectionBody) fileNames() []string {
+	var names []string
+	for _, file := range c.Files {
+		names = append(names, file.GetName())
+	}
+	for _, file := range c.Array {
+		if file != nil {
+			names = append(names, file.GetName())
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.ByName)) {
+		names = append(names, key+"="+c.ByName[key].GetName())
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.ByID)) {
+		names = append(names, strconv.Itoa(key)+"="+c.ByID[key].GetName())
+	}
+	for _, nested := range c.Nested {
+		names = append(names, nested.File.GetName())
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Grouped)) {
+		for _, file := range c.Grouped[key] {
+			names = append(names, key+"="+file.GetName())
+		}
+	}
+	return names
+}
+
+func TestDecoderCollections(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     []string
+		wantPath string
+	}{
+		{
+			name:  "decodes the slices of proto messages",
+			input: `{"files":[{"name":"a.proto"},null,{"name":"b.proto"}]}`,
+			want:  []string{"a.proto", "", "b.proto"},
+		},
+		{
+			name:  "decodes the arrays of proto messages and ignores the extra elements",
+			input: `{"array":[{"name":"a.proto"},{"name":"b.proto"},{"name":"c.proto"}]}`,
+			want:  []string{"a.proto", "b.proto"},
+		},
+		{
+			name:  "decodes the maps of proto messages by string and integer keys",
+			input: `{"by_name":{"a":{"name":"a.proto"}},"by_id":{"2":{"name":"b.proto"},"1":{"name":"c.proto"}}}`,
+			want:  []string{"a=a.proto", "1=c.proto", "2=b.proto"},
+		},
+		{
+			name:  "decodes the slices of structs with proto message fields",
+			input: `{"nested":[{"file":{"name":"a.proto"}},{"file":{"name":"b.proto"}}]}`,
+			want:  []string{"a.proto", "b.proto"},
+		},
+		{
+			name:  "decodes the nested collections",
+			input: `{"grouped":{"x":[{"name":"a.proto"},{"name":"b.proto"}],"y":[]}}`,
+			want:  []string{"x=a.proto", "x=b.proto"},
+		},
+		{
+			name:  "leaves the null collections empty",
+			input: `{"files":null,"by_name":null,"nested":null}`,
+		},
+		{
+			name:     "locates the invalid proto messages by index",
+			input:    `{"files":[{"name":"a.proto"},{"name":1}]}`,
+			wantPath: "/files/1",
+		},
+		{
+			name:     "locates the invalid proto messages by key",
+			input:    `{"grouped":{"x":[{"name":"a.proto"},{"name":true}]}}`,
+			wantPath: "/grouped/x/1",
+		},
+		{
+			name:     "rejects the invalid map keys",
+			input:    `{"by_id":{"one":{"name":"a.proto"}}}`,
+			wantPath: "/by_id/one",
+		},
+		{
+			name:     "rejects the non array values",
+			input:    `{"files":{"name":"a.proto"}}`,
+			wantPath: "/files",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var dest collectionBody
+				err := NewDecoder(nil).DecodeReader(strings.NewReader(test.input), &dest)
+
+				// Check the location of the error
+				if test.wantPath != "" {
+					var decodeErr *gojsondecoder.DecodeError
+					if !errors.As(err, &decodeErr) {
+						t.Fatalf("error = %v, want a *DecodeError", err)
+					}
+					if decodeErr.Path != test.wantPath {
+						t.Errorf("path = %q, want %q", decodeErr.Path, test.wantPath)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+
+				// Check the decoded elements
+				if got := dest.fileNames(); !slices.Equal(got, test.want) {
+					t.Errorf("files = %q, want %q", got, test.want)
+				}
+			},
+		)
+	}
+}
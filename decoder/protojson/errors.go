@@ -7,6 +7,8 @@ import (
 const (
 	ErrFieldNotHandled      = "field not handled on decoding: %s"
 	ErrFieldNotProtoMessage = "field %s is not a proto message"
+	ErrInvalidMapKey        = "invalid map key %q for %s"
+	ErrTypeNotStruct        = "type %s is not a struct or a pointer to a struct"
 )
 
 var (
@@ -15,4 +17,7 @@ var (
 	ErrNilMapper                  = errors.New("decoder mapper is nil")
 	ErrNilDestinationInstance     = errors.New("nil destination instance")
 	ErrNilDestination             = errors.New("nil destination")
+	ErrNilType                    = errors.New("nil type")
+	ErrExpectedArray              = errors.New("expected a JSON array")
+	ErrExpectedObject             = errors.New("expected a JSON object")
 )
@@ -1,25 +1,33 @@
 package protojson
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 
 	goreflect "github.com/ralvarezdev/go-reflect"
 	gostringsjson "github.com/ralvarezdev/go-strings/json"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
 )
 
 type (
 	// Mapper is the struct to hold precomputed marshal by reflection functions
 	Mapper struct {
-		reflectType        reflect.Type
-		isProtoMessage     bool
-		regularFields      map[string]struct{}
-		protoMessageFields map[string]struct{}
-		jsonFieldNames     map[string]string
-		nestedStructs      map[string]*Mapper
+		reflectType           reflect.Type
+		isProtoMessage        bool
+		regularFields         map[string]struct{}
+		protoMessageFields    map[string]struct{}
+		protoCollectionFields map[string]*collectionMapper
+		jsonFieldNames        map[string]string
+		nestedStructs         map[string]*Mapper
 	}
 )
 
@@ -27,7 +35,7 @@ type (
 //
 // Parameters:
 //
-// - destinationInstance: the destination instance to create the mapper for
+// - destinationInstance: the destination instance to create the mapper for, it can be a nil pointer to the struct
 //
 // Returns:
 //
@@ -40,34 +48,89 @@ func NewMapper(
 	if destinationInstance == nil {
 		return nil, ErrNilDestinationInstance
 	}
+	return NewMapperForType(reflect.TypeOf(destinationInstance))
+}
+
+// NewMapperForType creates a new Mapper instance from the destination type. The fields are classified from their
+// static types, so the mapper is valid for any value of the type and can be built at init time
+//
+// Parameters:
+//
+// - reflectType: the proto.Message type, the struct type or the pointer to the struct type to create the mapper for
+//
+// Returns:
+//
+// - *Mapper: the new Mapper instance
+// - error: the error if the type is nil or is not a struct
+func NewMapperForType(reflectType reflect.Type) (*Mapper, error) {
+	// Check if the type is nil
+	if reflectType == nil {
+		return nil, ErrNilType
+	}
 
 	// Check if the destination is a proto.Message
-	_, ok := destinationInstance.(proto.Message)
-	if ok {
+	if reflectType.Implements(protoMessageType) {
 		return &Mapper{
 			isProtoMessage: true,
 		}, nil
 	}
 
+	// Dereference the pointer type and check if it is a struct
+	if reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	if reflectType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(ErrTypeNotStruct, reflectType)
+	}
+	return newMapper(reflectType, make(map[reflect.Type]*Mapper))
+}
+
+// NewMapperFor creates a new Mapper instance from the type parameter, as NewMapperForType does
+//
+// Returns:
+//
+// - *Mapper: the new Mapper instance
+// - error: the error if the type parameter is not a proto.Message, a struct or a pointer to a struct
+func NewMapperFor[T any]() (*Mapper, error) {
+	return NewMapperForType(reflect.TypeFor[T]())
+}
+
+// newMapper creates a new Mapper instance from the struct type, reusing the mappers of the struct types that are
+// being built so the recursive types are mapped once
+//
+// Parameters:
+//
+// - reflectType: the struct type to create the mapper for
+// - building: the mappers of the struct types that are being built, by type
+//
+// Returns:
+//
+// - *Mapper: the new Mapper instance
+// - error: the error if any
+func newMapper(
+	reflectType reflect.Type,
+	building map[reflect.Type]*Mapper,
+) (*Mapper, error) {
 	// Create the maps to hold field information
 	regularFields := make(map[string]struct{})
 	protoMessageFields := make(map[string]struct{})
+	protoCollectionFields := make(map[string]*collectionMapper)
 	nestedStructs := make(map[string]*Mapper)
 	jsonFieldNames := make(map[string]string)
 
-	// Get the reflect type and value of the destination instance
-	reflectType := goreflect.GetDereferencedType(destinationInstance)
-	reflectValue := goreflect.GetDereferencedValue(destinationInstance)
+	// Register the mapper before mapping the fields, so the collections and the pointers of the same type reuse it
+	mapper := &Mapper{}
+	building[reflectType] = mapper
 
 	// Check if the type is a struct
-	for i := 0; i < reflectValue.NumField(); i++ {
+	for i := 0; i < reflectType.NumField(); i++ {
 		// Get the field and its type
 		structField := reflectType.Field(i)
-		fieldValue := reflectValue.Field(i)
 		fieldName := structField.Name
 
-		// Check if the field can be set
-		if !fieldValue.CanSet() {
+		// Check if the field can be set, the nested struct values are not addressable so the export status is
+		// checked instead
+		if !structField.IsExported() {
 			continue
 		}
 
@@ -86,28 +149,29 @@ func NewMapper(
 		// Store the JSON field name
 		jsonFieldNames[fieldName] = jsonFieldName
 
-		// Get the field interface
-		fieldValueInterface := fieldValue.Interface()
-
 		// Check if the field is a proto.Message
-		if _, protoOk := fieldValueInterface.(proto.Message); protoOk {
+		if structField.Type.Implements(protoMessageType) {
 			// Store the proto.Message field
 			protoMessageFields[fieldName] = struct{}{}
 			continue
 		}
 
-		// Dereference pointer if necessary
-		if fieldValue.Kind() == reflect.Ptr {
-			fieldValue = fieldValue.Elem()
+		// Check if the field is a collection of proto.Message or of structs with proto.Message fields
+		fieldCollectionMapper, err := newCollectionMapper(structField.Type, building)
+		if err != nil {
+			return nil, err
+		}
+		if fieldCollectionMapper != nil {
+			protoCollectionFields[fieldName] = fieldCollectionMapper
+			continue
 		}
 
-		// Check if the field is a struct
-		if fieldValue.Kind() == reflect.Struct {
-			// Create a nested mapper for the struct field
-			nestedMapper, nestedErr := NewMapper(fieldValueInterface)
-			if nestedErr != nil {
-				return nil, nestedErr
-			}
+		// Check if the field is a struct or a pointer to a struct at any depth
+		nestedMapper, err := newNestedMapper(structField.Type, building)
+		if err != nil {
+			return nil, err
+		}
+		if nestedMapper != nil {
 			nestedStructs[fieldName] = nestedMapper
 			continue
 		}
@@ -115,22 +179,125 @@ func NewMapper(
 		// Regular field
 		regularFields[fieldName] = struct{}{}
 	}
-	return &Mapper{
-		reflectType:        reflectType,
-		isProtoMessage:     false,
-		regularFields:      regularFields,
-		protoMessageFields: protoMessageFields,
-		nestedStructs:      nestedStructs,
-	}, nil
+	*mapper = Mapper{
+		reflectType:           reflectType,
+		isProtoMessage:        false,
+		regularFields:         regularFields,
+		protoMessageFields:    protoMessageFields,
+		protoCollectionFields: protoCollectionFields,
+		jsonFieldNames:        jsonFieldNames,
+		nestedStructs:         nestedStructs,
+	}
+	return mapper, nil
+}
+
+// newNestedMapper returns the mapper of a struct field, or of a pointer to struct field at any depth
+//
+// Parameters:
+//
+//   - fieldType: the type of the field
+//   - building: the mappers of the struct types that are being built, by type
+//
+// Returns:
+//
+//   - *Mapper: the mapper of the struct type, nil if the field is not a struct, is a proto.Message struct, or is a
+//     struct or a pointer to a struct without proto.Message fields, so it is decoded as a regular field, as the
+//     encoder leaves it to encoding/json
+//   - error: the error if the mapper of the struct type cannot be created
+func newNestedMapper(fieldType reflect.Type, building map[reflect.Type]*Mapper) (*Mapper, error) {
+	// Dereference the pointer type at any depth
+	structType := fieldType
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct || reflect.PointerTo(structType).Implements(protoMessageType) {
+		return nil, nil
+	}
+
+	// Decode the structs without proto.Message fields as regular fields, they are checked from their type alone so
+	// their fields do not need JSON tags
+	if !hasProtoMessageFields(structType, make(map[reflect.Type]struct{})) {
+		return nil, nil
+	}
+
+	// Reuse the mapper of the struct type if it is already being built, it is a recursive type
+	if nestedMapper, ok := building[structType]; ok {
+		return nestedMapper, nil
+	}
+	return newMapper(structType, building)
+}
+
+// hasProtoMessageFields returns whether the struct type has proto.Message fields, directly, in its collections or in
+// its nested structs, as the mapper classifies them
+//
+// Parameters:
+//
+//   - structType: the struct type to check
+//   - visited: the struct types already checked, the recursive types are checked once
+//
+// Returns:
+//
+// - bool: whether the struct type has proto.Message fields
+func hasProtoMessageFields(structType reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[structType]; ok {
+		return false
+	}
+	visited[structType] = struct{}{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		// Check the proto.Message fields and the collections of them
+		fieldType := structField.Type
+		if fieldType.Implements(protoMessageType) || isProtoMessageCollection(fieldType, visited) {
+			return true
+		}
+
+		// Check the nested structs and pointers to structs at any depth
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct &&
+			!reflect.PointerTo(fieldType).Implements(protoMessageType) &&
+			hasProtoMessageFields(fieldType, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether the mapper was built for the type of the destination
+//
+// Parameters:
+//
+//   - dest: The destination
+//
+// Returns:
+//
+//   - bool: whether the mapper is not nil and was built for the type of the destination, any proto.Message matches
+//     the mappers built for a proto.Message
+func (m *Mapper) matches(dest any) bool {
+	if m == nil {
+		return false
+	}
+	if m.isProtoMessage {
+		_, ok := dest.(proto.Message)
+		return ok
+	}
+	return goreflect.GetDereferencedType(dest) == m.reflectType
 }
 
-// UnmarshalByReflection unmarshal JSON data into a destination using reflection
+// UnmarshalByReflection unmarshal JSON data into a destination using reflection, stopping at the first error
 //
 // Parameters:
 //
 //   - body: The JSON data to unmarshal
 //   - dest: The destination to unmarshal the JSON data into
-//   - unmarshalOptions: Options for unmarshalling proto messages (optional, can be nil)
+//   - unmarshalOptions: Options for unmarshalling proto messages, DiscardUnknown also applies to the members of the
+//     structs (optional, can be nil)
 //
 // Returns:
 //
@@ -139,6 +306,50 @@ func (m *Mapper) UnmarshalByReflection(
 	body []byte,
 	dest any,
 	unmarshalOptions *protojson.UnmarshalOptions,
+) error {
+	return m.unmarshal(context.Background(), body, dest, unmarshalOptions, false)
+}
+
+// UnmarshalAllByReflection unmarshal JSON data into a destination using reflection, decoding every valid field and
+// collecting the errors of the others
+//
+// Parameters:
+//
+//   - body: The JSON data to unmarshal
+//   - dest: The destination to unmarshal the JSON data into
+//   - unmarshalOptions: Options for unmarshalling proto messages, DiscardUnknown also applies to the members of the
+//     structs (optional, can be nil)
+//
+// Returns:
+//
+//   - error: The DecodeErrors if any
+func (m *Mapper) UnmarshalAllByReflection(
+	body []byte,
+	dest any,
+	unmarshalOptions *protojson.UnmarshalOptions,
+) error {
+	return m.unmarshal(context.Background(), body, dest, unmarshalOptions, true)
+}
+
+// unmarshal unmarshal JSON data into a destination using reflection, until the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - body: The JSON data to unmarshal
+//   - dest: The destination to unmarshal the JSON data into
+//   - unmarshalOptions: Options for unmarshalling proto messages (optional, can be nil)
+//   - collect: Whether to keep decoding the valid fields after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (m *Mapper) unmarshal(
+	ctx context.Context,
+	body []byte,
+	dest any,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
 ) error {
 	// Check if the mapper is nil
 	if m == nil {
@@ -156,6 +367,7 @@ func (m *Mapper) UnmarshalByReflection(
 	}
 
 	// Check if the destination is a proto.Message
+	var err error
 	if m.isProtoMessage {
 		// Ensure the destination is a proto.Message
 		parsedProtoMessage, ok := dest.(proto.Message)
@@ -164,24 +376,71 @@ func (m *Mapper) UnmarshalByReflection(
 		}
 
 		// Unmarshal directly into the proto.Message
-		return unmarshalOptions.Unmarshal(
+		err = unmarshalOptions.Unmarshal(
 			body,
 			parsedProtoMessage,
 		)
+	} else {
+		// Unmarshal the fields
+		err = m.unmarshalFields(ctx, body, dest, unmarshalOptions, collect)
 	}
 
-	// Initialize the map to hold intermediate JSON data
-	var tempDest map[string]any
+	// The walk was interrupted, the errors found so far are not reported
+	if ctxErr := context.Cause(ctx); ctxErr != nil {
+		return ctxErr
+	}
+
+	// Locate the errors in the body
+	if collect {
+		err = gojsondecoder.NewDecodeErrors(err)
+	}
+	return gojsondecoder.ToDecodeError(body, err)
+}
+
+// unmarshalFields unmarshal the JSON object members into the fields of the destination struct, checking the context
+// before each field
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - body: The JSON object to unmarshal
+//   - dest: The destination struct to unmarshal the JSON object into
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid fields after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, a *DecodeError or DecodeErrors relative to the body for the members that cannot be
+//     decoded, or the cause of the context if it is done
+func (m *Mapper) unmarshalFields(
+	ctx context.Context,
+	body []byte,
+	dest any,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	// Initialize the map to hold the raw JSON of each field
+	var tempDest map[string]json.RawMessage
 	if unmarshalErr := json.Unmarshal(body, &tempDest); unmarshalErr != nil {
-		return unmarshalErr
+		err := gojsondecoder.ToDecodeError(body, unmarshalErr)
+		if decodeErr, ok := err.(*gojsondecoder.DecodeError); ok {
+			decodeErr.Expected = m.reflectType
+		}
+		return err
 	}
 
 	// Get the reflect value of the destination
 	reflectValue := goreflect.GetDereferencedValue(dest)
 
-	// Decode nested proto messages
-	mappedBody := make(map[string]any)
+	// Decode each field in place
+	var fieldErrs []error
+	knownMembers := make(map[string]struct{}, len(m.jsonFieldNames))
 	for i := 0; i < reflectValue.NumField(); i++ {
+		// Check the context
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			return ctxErr
+		}
+
 		// Get the field and its type
 		structField := m.reflectType.Field(i)
 		fieldName := structField.Name
@@ -196,81 +455,261 @@ func (m *Mapper) UnmarshalByReflection(
 		}
 
 		// Get the corresponding body field
+		knownMembers[jsonFieldName] = struct{}{}
 		bodyField, ok := tempDest[jsonFieldName]
 		if !ok {
 			continue
 		}
 
-		// Check if the field is a regular field
-		if _, regularOk := m.regularFields[fieldName]; regularOk {
-			// Directly map the body field
-			mappedBody[fieldName] = bodyField
+		// Decode the body field according to the field kind
+		fieldErr := m.unmarshalField(ctx, bodyField, fieldName, fieldValue, unmarshalOptions, collect)
+		if fieldErr == nil {
 			continue
 		}
 
-		// Check if the field is a proto.Message
-		if _, protoOk := m.protoMessageFields[fieldName]; protoOk {
-			// Marshal the body field to JSON
-			marshaledField, err := json.Marshal(bodyField)
-			if err != nil {
-				return err
-			}
+		// Locate the error in the body, in the collect mode it is kept and the next field is decoded
+		fieldErr = newFieldDecodeError(body, bodyField, jsonFieldName, fieldType, fieldErr)
+		if !collect {
+			return fieldErr
+		}
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
 
-			// Create a new instance of the proto.Message if it's a pointer and is nil
-			if fieldValue.Kind() == reflect.Ptr {
-				fieldValue.Set(reflect.New(fieldType.Elem()))
+	// Report the members that do not match a field unless they are discarded, ordered by offset
+	if !unmarshalOptions.DiscardUnknown {
+		var unknownErrs []error
+		for name := range tempDest {
+			if _, ok := knownMembers[name]; ok {
+				continue
 			}
+			unknownErrs = append(
+				unknownErrs, gojsondecoder.NewDecodeError(
+					gojsondecoder.AppendPointerToken("", name),
+					gojsondecoder.Position{Offset: memberOffset(body, name)},
+					nil,
+					gojsondecoder.ErrUnknownField,
+				),
+			)
+		}
+		slices.SortFunc(
+			unknownErrs, func(a, b error) int {
+				return cmp.Compare(
+					a.(*gojsondecoder.DecodeError).Offset,
+					b.(*gojsondecoder.DecodeError).Offset,
+				)
+			},
+		)
+		if len(unknownErrs) > 0 && !collect {
+			return unknownErrs[0]
+		}
+		fieldErrs = append(fieldErrs, unknownErrs...)
+	}
+	return gojsondecoder.NewDecodeErrors(fieldErrs...)
+}
 
-			// Get the interface of the field value
-			fieldValueInterface := fieldValue.Interface()
+// unmarshalField unmarshal a JSON object member into a field of the destination struct
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - bodyField: The raw value of the member
+//   - fieldName: The name of the struct field
+//   - fieldValue: The addressable struct field value
+//   - unmarshalOptions: Options for unmarshalling proto messages
+//   - collect: Whether to keep decoding the valid fields after an error and return every error as DecodeErrors
+//
+// Returns:
+//
+//   - error: The error if any, relative to the member
+func (m *Mapper) unmarshalField(
+	ctx context.Context,
+	bodyField []byte,
+	fieldName string,
+	fieldValue reflect.Value,
+	unmarshalOptions *protojson.UnmarshalOptions,
+	collect bool,
+) error {
+	// Check if the field is a regular field
+	if _, regularOk := m.regularFields[fieldName]; regularOk {
+		// Unmarshal the body field directly into the struct field
+		disallowUnknownFields := !unmarshalOptions.DiscardUnknown
+		if collect {
+			return gojsondecoderjson.UnmarshalAll(bodyField, fieldValue.Addr().Interface(), disallowUnknownFields)
+		}
+		return gojsondecoderjson.Unmarshal(
+			bodyField,
+			fieldValue.Addr().Interface(),
+			disallowUnknownFields,
+		)
+	}
 
-			// Parse field value interface as proto.Message
-			protoMessage, parsedProtoOk := fieldValueInterface.(proto.Message)
-			if !parsedProtoOk {
-				return fmt.Errorf(ErrFieldNotProtoMessage, fieldName)
-			}
+	// Check if the field is a proto.Message
+	if _, protoOk := m.protoMessageFields[fieldName]; protoOk {
+		// The null proto.Message are left nil, as encoding/json does for pointers
+		if fieldValue.Kind() == reflect.Ptr && bytes.Equal(bodyField, nullLiteral) {
+			fieldValue.SetZero()
+			return nil
+		}
 
-			// Unmarshal the JSON into the proto.Message field
-			if unmarshalErr := unmarshalOptions.Unmarshal(
-				marshaledField,
-				protoMessage,
-			); unmarshalErr != nil {
-				return unmarshalErr
-			}
+		// Create a new instance of the proto.Message if it's a pointer and is nil
+		if fieldValue.Kind() == reflect.Ptr {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
 
-			// Map the proto.Message field
-			mappedBody[fieldName] = fieldValueInterface
-			continue
+		// Get the interface of the field value
+		fieldValueInterface := fieldValue.Interface()
+
+		// Parse field value interface as proto.Message
+		protoMessage, parsedProtoOk := fieldValueInterface.(proto.Message)
+		if !parsedProtoOk {
+			return fmt.Errorf(ErrFieldNotProtoMessage, fieldName)
 		}
 
-		// Check if the field is a nested struct
-		if nestedMapper, nestedOk := m.nestedStructs[fieldName]; nestedOk {
-			// Marshal the body field to JSON
-			marshaledNestedBody, err := json.Marshal(bodyField)
-			if err != nil {
-				return err
-			}
+		// Unmarshal the JSON into the proto.Message field
+		return unmarshalOptions.Unmarshal(
+			bodyField,
+			protoMessage,
+		)
+	}
 
-			// Get the interface of the field value
-			fieldValueInterface := fieldValue.Addr().Interface()
+	// Check if the field is a collection of proto.Message or of structs with proto.Message fields
+	if fieldCollectionMapper, collectionOk := m.protoCollectionFields[fieldName]; collectionOk {
+		// Unmarshal each element of the collection
+		return fieldCollectionMapper.unmarshal(ctx, bodyField, fieldValue, unmarshalOptions, collect)
+	}
 
-			// Unmarshal the body field by reflection
-			if nestedErr := nestedMapper.UnmarshalByReflection(marshaledNestedBody, fieldValueInterface, unmarshalOptions); nestedErr != nil {
-				return nestedErr
+	// Check if the field is a nested struct
+	if nestedMapper, nestedOk := m.nestedStructs[fieldName]; nestedOk {
+		// The null pointers are left nil, as encoding/json does
+		if fieldValue.Kind() == reflect.Ptr && bytes.Equal(bodyField, nullLiteral) {
+			fieldValue.SetZero()
+			return nil
+		}
+
+		// Allocate the nil pointers at any depth
+		for fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
 			}
+			fieldValue = fieldValue.Elem()
+		}
 
-			// Set the mapped nested struct
-			mappedBody[fieldName] = fieldValueInterface
-			continue
+		// Unmarshal the body field by reflection
+		return nestedMapper.unmarshal(
+			ctx,
+			bodyField,
+			fieldValue.Addr().Interface(),
+			unmarshalOptions,
+			collect,
+		)
+	}
+
+	// The field type is not handled, return an error
+	return fmt.Errorf(ErrFieldNotHandled, fieldName)
+}
+
+// newFieldDecodeError creates the error returned when an object member cannot be decoded, located in the object
+//
+// Parameters:
+//
+//   - body: The JSON object
+//   - bodyField: The raw value of the member
+//   - jsonFieldName: The name of the member
+//   - fieldType: The type of the struct field the member is decoded into
+//   - err: The error returned while decoding the member
+//
+// Returns:
+//
+//   - error: The *DecodeError or DecodeErrors relative to the object
+func newFieldDecodeError(
+	body []byte,
+	bodyField []byte,
+	jsonFieldName string,
+	fieldType reflect.Type,
+	err error,
+) error {
+	return newNestedDecodeError(bodyField, jsonFieldName, memberOffset(body, jsonFieldName), fieldType, err)
+}
+
+// newNestedDecodeError creates the error returned when a value of a JSON object or array cannot be decoded, located
+// in the object or the array
+//
+// Parameters:
+//
+//   - data: The raw value
+//   - token: The member name or the index of the value
+//   - offset: The offset of the value in the object or the array, -1 if it is not known
+//   - expected: The type the value is decoded into
+//   - err: The error returned while decoding the value
+//
+// Returns:
+//
+//   - error: The *DecodeError or DecodeErrors relative to the object or the array
+func newNestedDecodeError(
+	data []byte,
+	token string,
+	offset int64,
+	expected reflect.Type,
+	err error,
+) error {
+	nestedErr := gojsondecoder.NestDecodeError(
+		data,
+		err,
+		token,
+		gojsondecoder.Position{Offset: offset},
+	)
+
+	// Set the expected type if the error does not define a more specific one
+	decodeErrs, ok := nestedErr.(gojsondecoder.DecodeErrors)
+	if !ok {
+		decodeErr, ok := nestedErr.(*gojsondecoder.DecodeError)
+		if !ok {
+			return nestedErr
+		}
+		decodeErrs = gojsondecoder.DecodeErrors{decodeErr}
+	}
+	for _, decodeErr := range decodeErrs {
+		if decodeErr.Expected == nil {
+			decodeErr.Expected = expected
 		}
+	}
+	return nestedErr
+}
 
-		// The field type is not handled, return an error
-		return fmt.Errorf(ErrFieldNotHandled, fieldName)
+// memberOffset returns the offset of the value of a member of a JSON object, the last one if the member is
+// repeated, as it is the one kept when decoding
+//
+// Parameters:
+//
+//   - body: The JSON object
+//   - name: The name of the member
+//
+// Returns:
+//
+//   - int64: The offset of the value, -1 if it is not found
+func memberOffset(body []byte, name string) int64 {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return -1
 	}
 
-	// Reflect the destination value
-	destValue := goreflect.GetDereferencedValue(dest)
+	offset := int64(-1)
+	for decoder.More() {
+		// Read the member name
+		token, err := decoder.Token()
+		if err != nil {
+			return offset
+		}
+		key, _ := token.(string)
 
-	// Map the body to the struct field
-	return goreflect.ValueToReflectStruct(mappedBody, destValue, false)
+		// Read the member value
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return offset
+		}
+		if key == name {
+			offset = decoder.InputOffset() - int64(len(value))
+		}
+	}
+	return offset
 }
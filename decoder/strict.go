@@ -0,0 +1,177 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+type (
+	// strictScanner walks a JSON document looking for the repeated member names of its objects
+	strictScanner struct {
+		data          []byte
+		decoder       *json.Decoder
+		duplicateKeys DuplicateKeys
+		duplicates    [][2]int64
+		errs          []error
+	}
+)
+
+// newStrictScanner creates a new strictScanner instance
+//
+// Parameters:
+//
+//   - data: the JSON document
+//   - duplicateKeys: the policy applied to the repeated member names
+//
+// Returns:
+//
+//   - *strictScanner: the new strictScanner instance
+func newStrictScanner(data []byte, duplicateKeys DuplicateKeys) *strictScanner {
+	return &strictScanner{
+		data:          data,
+		decoder:       json.NewDecoder(bytes.NewReader(data)),
+		duplicateKeys: duplicateKeys,
+	}
+}
+
+// scanValue reads the next value, recording the repeated members of its objects according to the policy. With
+// DuplicateKeysFirstWins, each repeated member is recorded as the span from the end of the previous member to the
+// end of its value. With DuplicateKeysLastWins, each replaced member is recorded as the span from its name to the
+// name of the next member, as protojson rejects the repeated members. So blanking them out keeps the object valid
+//
+// Parameters:
+//
+//   - path: the JSON Pointer to the value
+//
+// Returns:
+//
+//   - error: the syntax error if any
+func (s *strictScanner) scanValue(path string) error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		keys := make(map[string]int)
+		var nameOffsets []int64
+		for s.decoder.More() {
+			start := s.decoder.InputOffset()
+			nameOffsets = append(nameOffsets, skipSeparators(s.data, start))
+
+			// Read the member
+			token, err = s.decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			memberPath := AppendPointerToken(path, key)
+			if err = s.scanValue(memberPath); err != nil {
+				return err
+			}
+
+			// Apply the policy to the repeated member
+			index := len(nameOffsets) - 1
+			previousIndex, ok := keys[key]
+			if !ok {
+				keys[key] = index
+				continue
+			}
+			switch s.duplicateKeys {
+			case DuplicateKeysError:
+				position := PositionAt(s.data, nameOffsets[index])
+				s.errs = append(s.errs, NewDecodeError(memberPath, position, nil, ErrDuplicateKey))
+			case DuplicateKeysFirstWins:
+				s.duplicates = append(s.duplicates, [2]int64{start, s.decoder.InputOffset()})
+			case DuplicateKeysLastWins:
+				s.duplicates = append(
+					s.duplicates,
+					[2]int64{nameOffsets[previousIndex], nameOffsets[previousIndex+1]},
+				)
+				keys[key] = index
+			}
+		}
+	case json.Delim('['):
+		for i := 0; s.decoder.More(); i++ {
+			if err = s.scanValue(AppendPointerToken(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// Read the end of the object or the array
+	_, err = s.decoder.Token()
+	return err
+}
+
+// skipSeparators returns the offset of the first byte from the given offset that is not whitespace or a separator
+//
+// Parameters:
+//
+//   - data: the JSON document
+//   - offset: the offset to start from
+//
+// Returns:
+//
+//   - int64: the offset of the byte, the length of the data if there is none
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,:"), data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// invalidUTF8Offset returns the offset of the first invalid UTF-8 sequence of the data
+//
+// Parameters:
+//
+//   - data: the data
+//
+// Returns:
+//
+//   - int64: the offset of the sequence, -1 if the data is valid UTF-8
+func invalidUTF8Offset(data []byte) int64 {
+	if utf8.Valid(data) {
+		return -1
+	}
+	for offset := 0; offset < len(data); {
+		r, size := utf8.DecodeRune(data[offset:])
+		if r == utf8.RuneError && size == 1 {
+			return int64(offset)
+		}
+		offset += size
+	}
+	return -1
+}
+
+// blankSpans returns a copy of the data with the spans replaced by whitespace, keeping the newlines so the lines of
+// the remaining data do not change
+//
+// Parameters:
+//
+//   - data: the data
+//   - spans: the start and end offsets of the spans
+//
+// Returns:
+//
+//   - []byte: the blanked copy, or the data itself if there are no spans
+func blankSpans(data []byte, spans [][2]int64) []byte {
+	if len(spans) == 0 {
+		return data
+	}
+
+	blanked := bytes.Clone(data)
+	for _, span := range spans {
+		for i := span[0]; i < span[1]; i++ {
+			if blanked[i] != '\n' {
+				blanked[i] = ' '
+			}
+		}
+	}
+	return blanked
+}
@@ -0,0 +1,73 @@
+package decoder
+
+import (
+	"context"
+	"reflect"
+)
+
+// Decode decodes the body with the decoder into a new value of the type parameter, so the call sites do not declare
+// the destination themselves. If the type parameter is a pointer, the pointed value is allocated first
+//
+// Parameters:
+//
+//   - decoder: The decoder used to decode the body
+//   - body: The body to decode, an io.Reader, a string or a []byte
+//
+// Returns:
+//
+//   - T: The decoded value, it can be partially decoded if there is an error, such as in the collect mode
+//   - error: The error if any
+func Decode[T any](decoder Decoder, body any) (T, error) {
+	var value T
+	err := DecodeIntoValue(decoder, body, reflect.ValueOf(&value))
+	return value, err
+}
+
+// DecodeContext decodes the body with the decoder into a new value of the type parameter, as Decode does, stopping
+// once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - decoder: The decoder used to decode the body
+//   - body: The body to decode, an io.Reader, a string or a []byte
+//
+// Returns:
+//
+//   - T: The decoded value, it can be partially decoded if there is an error, such as in the collect mode
+//   - error: The error if any, the cause of the context if it is done
+func DecodeContext[T any](ctx context.Context, decoder Decoder, body any) (T, error) {
+	var value T
+
+	// Check the body
+	if body == nil {
+		return value, ErrNilBody
+	}
+
+	// Check the body type
+	reader, err := ToReader(body)
+	if err != nil {
+		return value, err
+	}
+
+	err = DecodeReaderContext(ctx, decoder, reader, newDestination(reflect.ValueOf(&value)))
+	return value, err
+}
+
+// newDestination returns the destination to decode into for the given pointer, allocating the pointed value first
+// if it is also a pointer
+//
+// Parameters:
+//
+//   - ptrValue: The pointer to the value to decode into
+//
+// Returns:
+//
+//   - any: The destination
+func newDestination(ptrValue reflect.Value) any {
+	if elemValue := ptrValue.Elem(); elemValue.Kind() == reflect.Ptr {
+		elemValue.Set(reflect.New(elemValue.Type().Elem()))
+		return elemValue.Interface()
+	}
+	return ptrValue.Interface()
+}
@@ -2,8 +2,24 @@ package decoder
 
 import (
 	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+
+	gojsoncontextio "github.com/ralvarezdev/go-json/internal/contextio"
+)
+
+var (
+	// protoJSONPositionRegexp matches the position reported by the protojson errors
+	protoJSONPositionRegexp = regexp.MustCompile(`\(line (\d+):(\d+)\)`)
 )
 
 // ToReader converts an any to an io.Reader
@@ -28,3 +44,436 @@ func ToReader(reader any) (io.Reader, error) {
 		return nil, ErrInvalidInstance
 	}
 }
+
+// DecodeIntoValue decodes the body into the value pointed by the given pointer, allocating the pointed value first
+// if it is also a pointer, so each decoded element gets its own instance
+//
+// Parameters:
+//
+//   - decoder: The decoder used to decode the body
+//   - body: The body to decode
+//   - ptrValue: The pointer to the value to decode the body into
+//
+// Returns:
+//
+//   - error: The error if any
+func DecodeIntoValue(decoder Decoder, body any, ptrValue reflect.Value) error {
+	// Check the decoder
+	if decoder == nil {
+		return ErrNilDecoder
+	}
+
+	// Allocate the pointed value if it is a pointer
+	return decoder.Decode(body, newDestination(ptrValue))
+}
+
+// DecodeReaderContext decodes the body from a reader with the decoder, stopping once the context is done. The
+// decoders that do not implement ContextDecoder are given a reader that fails once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - decoder: The decoder used to decode the body
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func DecodeReaderContext(
+	ctx context.Context,
+	decoder Decoder,
+	reader io.Reader,
+	dest any,
+) error {
+	// Check the decoder
+	if decoder == nil {
+		return ErrNilDecoder
+	}
+
+	// Use the context support of the decoder if it has one
+	if contextDecoder, ok := decoder.(ContextDecoder); ok {
+		return contextDecoder.DecodeReaderContext(ctx, reader, dest)
+	}
+
+	// Check the context before reading
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+	contextReader, stop := gojsoncontextio.NewReader(ctx, reader)
+	defer stop()
+	return decoder.DecodeReader(contextReader, dest)
+}
+
+// AppendPointerToken appends a reference token to an RFC 6901 JSON Pointer, escaping it
+//
+// Parameters:
+//
+//   - pointer: The JSON Pointer, empty for the whole document
+//   - token: The reference token, such as an object member name or an array index
+//
+// Returns:
+//
+//   - string: The JSON Pointer to the referenced value
+func AppendPointerToken(pointer, token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return pointer + "/" + token
+}
+
+// ToDecodeError converts the error returned while decoding the data into a *DecodeError relative to the data. The
+// encoding/json syntax and type errors and the protojson errors are located, any other error is wrapped as is. The
+// entries of DecodeErrors are converted one by one and ordered by offset
+//
+// Parameters:
+//
+//   - data: The decoded data, nil if it is not available, then the line and the column are not computed
+//   - err: The error returned while decoding the data
+//
+// Returns:
+//
+//   - error: The *DecodeError or the DecodeErrors, nil if the error is nil
+func ToDecodeError(data []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if decodeErrs, ok := err.(DecodeErrors); ok {
+		locatedErrs := make(DecodeErrors, len(decodeErrs))
+		for i, decodeErr := range decodeErrs {
+			locatedErrs[i] = toDecodeError(data, decodeErr)
+		}
+
+		// Order the entries by offset, the ones without a location go last
+		slices.SortStableFunc(
+			locatedErrs, func(a, b *DecodeError) int {
+				if (a.Offset < 0) != (b.Offset < 0) {
+					return cmp.Compare(b.Offset, a.Offset)
+				}
+				return cmp.Compare(a.Offset, b.Offset)
+			},
+		)
+		return locatedErrs
+	}
+	return toDecodeError(data, err)
+}
+
+// toDecodeError converts the error returned while decoding the data into a *DecodeError relative to the data
+//
+// Parameters:
+//
+//   - data: The decoded data, nil if it is not available
+//   - err: The error returned while decoding the data
+//
+// Returns:
+//
+//   - *DecodeError: The new DecodeError instance
+func toDecodeError(data []byte, err error) *DecodeError {
+	decodeErr := newDecodeErrorFromCause(err)
+
+	// The encoding/json type errors are detected after reading the value, so they are located at its start, as the
+	// errors with a path but without a location
+	var typeErr *json.UnmarshalTypeError
+	_, isDecodeErr := err.(*DecodeError)
+	isTypeErr := !isDecodeErr && errors.As(err, &typeErr)
+	isUnlocated := decodeErr.Path != "" && decodeErr.Offset < 0 && decodeErr.Line == 0
+	if data != nil && (isTypeErr || isUnlocated) {
+		if offset := pointerOffset(data, decodeErr.Path); offset >= 0 {
+			decodeErr.Offset = offset
+		}
+	}
+
+	// The protojson syntax errors of invalid JSON are located at the offset found by the encoding/json scanner, the
+	// line and the column reported by protojson are the fallback
+	if data != nil && decodeErr.Offset < 0 && decodeErr.Line > 0 && errors.Is(decodeErr.Err, ErrInvalidSyntax) {
+		decodeErr.Offset = syntaxErrorOffset(data)
+	}
+
+	// Complete the position with the data, the line and the column are computed from the offset
+	if data != nil {
+		var position Position
+		switch {
+		case decodeErr.Offset >= 0:
+			position = PositionAt(data, decodeErr.Offset)
+		case decodeErr.Line > 0:
+			position = positionOfLine(data, decodeErr.Line, decodeErr.Column)
+		default:
+			return decodeErr
+		}
+		decodeErr.Offset = position.Offset
+		decodeErr.Line = position.Line
+		decodeErr.Column = position.Column
+	}
+	return decodeErr
+}
+
+// syntaxErrorOffset returns the offset of the first syntax error of the data
+//
+// Parameters:
+//
+//   - data: The JSON data
+//
+// Returns:
+//
+//   - int64: The offset of the offending byte, -1 if the data is valid JSON
+func syntaxErrorOffset(data []byte) int64 {
+	var value json.RawMessage
+	var syntaxErr *json.SyntaxError
+	if err := json.Unmarshal(data, &value); errors.As(err, &syntaxErr) {
+		return max(syntaxErr.Offset-1, 0)
+	}
+	return -1
+}
+
+// NestDecodeError converts the error returned while decoding the data, a value that starts at the given position of
+// an enclosing document, into a *DecodeError relative to the enclosing document. The entries of DecodeErrors are
+// converted one by one
+//
+// Parameters:
+//
+//   - data: The decoded data of the value
+//   - err: The error returned while decoding the data
+//   - token: The reference token of the value in the enclosing document, empty if the value is the root of the
+//     enclosing document
+//   - start: The position of the value in the enclosing document, the unknown parts are left unknown
+//
+// Returns:
+//
+//   - error: The *DecodeError or the DecodeErrors, nil if the error is nil
+func NestDecodeError(
+	data []byte,
+	err error,
+	token string,
+	start Position,
+) error {
+	if err == nil {
+		return nil
+	}
+	if decodeErrs, ok := err.(DecodeErrors); ok {
+		nestedErrs := make(DecodeErrors, len(decodeErrs))
+		for i, decodeErr := range decodeErrs {
+			nestedErrs[i] = nestDecodeError(data, decodeErr, token, start)
+		}
+		return nestedErrs
+	}
+	return nestDecodeError(data, err, token, start)
+}
+
+// nestDecodeError converts the error returned while decoding the data, a value that starts at the given position of
+// an enclosing document, into a *DecodeError relative to the enclosing document
+//
+// Parameters:
+//
+//   - data: The decoded data of the value
+//   - err: The error returned while decoding the data
+//   - token: The reference token of the value in the enclosing document
+//   - start: The position of the value in the enclosing document
+//
+// Returns:
+//
+//   - *DecodeError: The new DecodeError instance
+func nestDecodeError(
+	data []byte,
+	err error,
+	token string,
+	start Position,
+) *DecodeError {
+	decodeErr := toDecodeError(data, err)
+
+	// Prefix the path with the token and relocate the position
+	if token != "" {
+		decodeErr.Path = AppendPointerToken("", token) + decodeErr.Path
+	}
+	position := start.Nest(decodeErr.Position())
+	decodeErr.Offset = position.Offset
+	decodeErr.Line = position.Line
+	decodeErr.Column = position.Column
+	return decodeErr
+}
+
+// Validate validates the destination if it implements Validator, the causes of the returned errors are wrapped with
+// ErrValidation
+//
+// Parameters:
+//
+//   - dest: The decoded destination
+//
+// Returns:
+//
+//   - error: The DecodeErrors with the validation errors, nil if the destination is valid or does not implement
+//     Validator
+func Validate(dest any) error {
+	validator, ok := dest.(Validator)
+	if !ok {
+		return nil
+	}
+	validationErrs, ok := NewDecodeErrors(validator.Validate()).(DecodeErrors)
+	if !ok {
+		return nil
+	}
+
+	// Wrap the causes with ErrValidation, copying the errors so the ones of the validator are not modified
+	typedErrs := make(DecodeErrors, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		typedErr := *validationErr
+		if !errors.Is(typedErr.Err, ErrValidation) {
+			typedErr.Err = fmt.Errorf(ErrTypedCause, ErrValidation, typedErr.Err)
+		}
+		typedErrs[i] = &typedErr
+	}
+	return typedErrs
+}
+
+// newDecodeErrorFromCause creates a *DecodeError from the error returned while decoding, copying it if it already
+// is one, so the original error is not modified
+//
+// Parameters:
+//
+//   - err: The error returned while decoding
+//
+// Returns:
+//
+//   - *DecodeError: The new DecodeError instance
+func newDecodeErrorFromCause(err error) *DecodeError {
+	// Copy the error if it already is a DecodeError, keeping the wrapping error as the cause if it is wrapped
+	if decodeErr, ok := err.(*DecodeError); ok {
+		decodeErrCopy := *decodeErr
+		return &decodeErrCopy
+	}
+	var wrappedDecodeErr *DecodeError
+	if errors.As(err, &wrappedDecodeErr) {
+		decodeErrCopy := *wrappedDecodeErr
+		decodeErrCopy.Err = err
+		return &decodeErrCopy
+	}
+
+	// The encoding/json offsets are the number of bytes read, so the offending byte is the previous one
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return NewDecodeError("", Position{Offset: max(syntaxErr.Offset-1, 0)}, nil, err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		var path string
+		if typeErr.Field != "" {
+			for _, token := range strings.Split(typeErr.Field, ".") {
+				path = AppendPointerToken(path, token)
+			}
+		}
+		return NewDecodeError(path, Position{Offset: max(typeErr.Offset-1, 0)}, typeErr.Type, err)
+	}
+
+	// The protojson errors only report the line and the column
+	if matches := protoJSONPositionRegexp.FindStringSubmatch(err.Error()); matches != nil {
+		line, _ := strconv.Atoi(matches[1])
+		column, _ := strconv.Atoi(matches[2])
+		return NewDecodeError("", Position{Offset: -1, Line: line, Column: column}, nil, toTypedCause(err))
+	}
+	return NewDecodeError("", UnknownPosition, nil, toTypedCause(err))
+}
+
+// toTypedCause wraps the unknown field errors of encoding/json and protojson with ErrUnknownField, the syntax errors
+// of protojson with ErrInvalidSyntax and its other errors, such as the invalid field values, with ErrInvalidValue, as
+// neither package exports a typed error for them. Any other error is returned as is
+//
+// Parameters:
+//
+//   - err: The error returned while decoding
+//
+// Returns:
+//
+//   - error: The typed error
+func toTypedCause(err error) error {
+	if errors.Is(err, ErrUnknownField) || errors.Is(err, ErrInvalidSyntax) || errors.Is(err, ErrInvalidValue) {
+		return err
+	}
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "json: unknown field "):
+		return fmt.Errorf(ErrTypedCause, ErrUnknownField, err)
+	case !strings.HasPrefix(message, "proto:"):
+		return err
+	case strings.Contains(message, "unknown field "):
+		return fmt.Errorf(ErrTypedCause, ErrUnknownField, err)
+	case strings.Contains(message, "syntax error "):
+		return fmt.Errorf(ErrTypedCause, ErrInvalidSyntax, err)
+	default:
+		return fmt.Errorf(ErrTypedCause, ErrInvalidValue, err)
+	}
+}
+
+// pointerOffset returns the offset of the value referenced by an RFC 6901 JSON Pointer
+//
+// Parameters:
+//
+//   - data: The JSON document
+//   - pointer: The JSON Pointer, empty for the whole document
+//
+// Returns:
+//
+//   - int64: The offset of the value, -1 if it is not found
+func pointerOffset(data []byte, pointer string) int64 {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if pointer != "" {
+		for _, token := range strings.Split(pointer[1:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			if !skipToMember(decoder, token) {
+				return -1
+			}
+		}
+	}
+
+	// Skip the whitespace and the separators before the value
+	offset := decoder.InputOffset()
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n:,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// skipToMember reads the decoder until the value of the object member or the array element referenced by the token
+//
+// Parameters:
+//
+//   - decoder: The decoder positioned before an object or an array
+//   - token: The reference token
+//
+// Returns:
+//
+//   - bool: Whether the value was found
+func skipToMember(decoder *json.Decoder, token string) bool {
+	delim, err := decoder.Token()
+	if err != nil {
+		return false
+	}
+
+	var skipped json.RawMessage
+	switch delim {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, keyErr := decoder.Token()
+			if keyErr != nil {
+				return false
+			}
+			if key == token {
+				return true
+			}
+			if err = decoder.Decode(&skipped); err != nil {
+				return false
+			}
+		}
+	case json.Delim('['):
+		index, indexErr := strconv.Atoi(token)
+		if indexErr != nil || index < 0 {
+			return false
+		}
+		for i := 0; decoder.More(); i++ {
+			if i == index {
+				return true
+			}
+			if err = decoder.Decode(&skipped); err != nil {
+				return false
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package decoder
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestToDecodeError(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantOffset int64
+		wantLine   int
+		wantColumn int
+		wantErr    error
+	}{
+		{
+			name:       "locates the syntax errors at the offending byte",
+			data:       "{\n  \"name\": }",
+			wantOffset: 12,
+			wantLine:   2,
+			wantColumn: 11,
+			wantErr:    ErrInvalidSyntax,
+		},
+		{
+			name:       "counts the columns in bytes after multibyte characters",
+			data:       "{\"name\": \"ñandú\", \"unknown\": 1}",
+			wantOffset: 20,
+			wantLine:   1,
+			wantColumn: 21,
+			wantErr:    ErrUnknownField,
+		},
+		{
+			name:       "locates the errors on the following lines",
+			data:       "{\n  \"name\": \"ñ\",\n  \"unknown\": 1\n}",
+			wantOffset: 20,
+			wantLine:   3,
+			wantColumn: 3,
+			wantErr:    ErrUnknownField,
+		},
+		{
+			name:       "types the invalid values",
+			data:       "{\"name\": 1}",
+			wantOffset: 9,
+			wantLine:   1,
+			wantColumn: 10,
+			wantErr:    ErrInvalidValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				data := []byte(test.data)
+				err := ToDecodeError(data, protojson.Unmarshal(data, &descriptorpb.FileDescriptorProto{}))
+
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) {
+					t.Fatalf("error = %v, want a *DecodeError", err)
+				}
+				if !errors.Is(decodeErr, test.wantErr) {
+					t.Errorf("error = %v, want %v", decodeErr, test.wantErr)
+				}
+				if decodeErr.Offset != test.wantOffset ||
+					decodeErr.Line != test.wantLine ||
+					decodeErr.Column != test.wantColumn {
+					t.Errorf(
+						"position = %d (%d:%d), want %d (%d:%d)",
+						decodeErr.Offset,
+						decodeErr.Line,
+						decodeErr.Column,
+						test.wantOffset,
+						test.wantLine,
+						test.wantColumn,
+					)
+				}
+			},
+		)
+	}
+}
+
+// validatedBody is a destination that validates itself
+type validatedBody struct {
+	errs []error
+}
+
+func (v validatedBody) Validate() error {
+	return errors.Join(v.errs...)
+}
+
+func TestValidate(t *testing.T) {
+	fieldErr := NewDecodeError("/name", UnknownPosition, nil, errors.New("name is required"))
+	err := Validate(validatedBody{errs: []error{fieldErr, errors.New("id is required")}})
+
+	// Every validation error is typed with ErrValidation and keeps its location
+	decodeErrs, ok := err.(DecodeErrors)
+	if !ok || len(decodeErrs) != 2 {
+		t.Fatalf("error = %v, want 2 DecodeErrors", err)
+	}
+	for _, decodeErr := range decodeErrs {
+		if !errors.Is(decodeErr, ErrValidation) {
+			t.Errorf("error = %v, want %v", decodeErr, ErrValidation)
+		}
+	}
+	if decodeErrs[0].Path != "/name" {
+		t.Errorf("path = %q, want \"/name\"", decodeErrs[0].Path)
+	}
+
+	// The errors of the validator are not modified
+	if errors.Is(fieldErr, ErrValidation) {
+		t.Errorf("validator error = %v, want it unmodified", fieldErr)
+	}
+	if err = Validate(validatedBody{}); err != nil {
+		t.Errorf("error = %v, want nil", err)
+	}
+}
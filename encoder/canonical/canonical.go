@@ -0,0 +1,257 @@
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf16"
+)
+
+const (
+	// hexDigits are the lowercase hexadecimal digits used to escape control characters
+	hexDigits = "0123456789abcdef"
+)
+
+// Canonicalize transforms the given JSON document into its RFC 8785 canonical form
+//
+// Parameters:
+//
+//   - data: The JSON document to canonicalize
+//
+// Returns:
+//
+//   - []byte: The canonical JSON bytes
+//   - error: The error if any
+func Canonicalize(data []byte) ([]byte, error) {
+	// Decode the document into generic values, keeping the numbers as they were written
+	jsonDecoder := json.NewDecoder(bytes.NewReader(data))
+	jsonDecoder.UseNumber()
+
+	value, err := decodeValue(jsonDecoder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check there is no data after the top-level value
+	if _, err := jsonDecoder.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrTrailingData
+	}
+
+	// Serialize the generic values
+	buffer := new(bytes.Buffer)
+	if err := appendValue(buffer, value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeValue decodes the next JSON value into generic values, rejecting the objects with duplicate member names as
+// RFC 8785 requires I-JSON input
+//
+// Parameters:
+//
+//   - jsonDecoder: The JSON decoder, with UseNumber enabled
+//
+// Returns:
+//
+//   - any: The generic JSON value
+//   - error: The error if any
+func decodeValue(jsonDecoder *json.Decoder) (any, error) {
+	token, err := jsonDecoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := make(map[string]any)
+		for jsonDecoder.More() {
+			token, err = jsonDecoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := token.(string)
+			if _, ok := object[key]; ok {
+				return nil, fmt.Errorf(ErrDuplicateKey, key)
+			}
+			if object[key], err = decodeValue(jsonDecoder); err != nil {
+				return nil, err
+			}
+		}
+		_, err = jsonDecoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := make([]any, 0)
+		for jsonDecoder.More() {
+			element, elementErr := decodeValue(jsonDecoder)
+			if elementErr != nil {
+				return nil, elementErr
+			}
+			array = append(array, element)
+		}
+		_, err = jsonDecoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// appendValue appends the canonical form of a generic JSON value to the buffer
+//
+// Parameters:
+//
+//   - buffer: The buffer to append to
+//   - value: The generic JSON value, as decoded by encoding/json with UseNumber
+//
+// Returns:
+//
+//   - error: The error if any
+func appendValue(buffer *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case bool:
+		buffer.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		return appendNumber(buffer, v)
+	case string:
+		appendString(buffer, v)
+	case []any:
+		buffer.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := appendValue(buffer, element); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	case map[string]any:
+		// Sort the keys by their UTF-16 code units
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, compareUTF16)
+
+		buffer.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			appendString(buffer, key)
+			buffer.WriteByte(':')
+			if err := appendValue(buffer, v[key]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	default:
+		return fmt.Errorf(ErrUnexpectedValue, value)
+	}
+	return nil
+}
+
+// appendNumber appends a number serialized as ECMAScript Number.prototype.toString does
+//
+// Parameters:
+//
+//   - buffer: The buffer to append to
+//   - number: The number to serialize
+//
+// Returns:
+//
+//   - error: The error if the number is not a finite IEEE 754 double
+func appendNumber(buffer *bytes.Buffer, number json.Number) error {
+	float, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil || math.IsInf(float, 0) || math.IsNaN(float) {
+		return fmt.Errorf(ErrInvalidNumber, number)
+	}
+
+	// Negative zero is serialized as zero
+	if float == 0 {
+		buffer.WriteByte('0')
+		return nil
+	}
+
+	// Use the exponential notation outside the [1e-6, 1e21) range
+	format := byte('f')
+	if absolute := math.Abs(float); absolute < 1e-6 || absolute >= 1e21 {
+		format = 'e'
+	}
+	serialized := strconv.AppendFloat(nil, float, format, -1, 64)
+
+	// Remove the leading zero of the exponent, e.g. 1e-07 to 1e-7
+	if format == 'e' {
+		n := len(serialized)
+		if n >= 4 && serialized[n-4] == 'e' && serialized[n-3] == '-' && serialized[n-2] == '0' {
+			serialized[n-2] = serialized[n-1]
+			serialized = serialized[:n-1]
+		}
+	}
+	buffer.Write(serialized)
+	return nil
+}
+
+// appendString appends a string with the minimal escaping required by RFC 8785
+//
+// Parameters:
+//
+//   - buffer: The buffer to append to
+//   - s: The string to serialize
+func appendString(buffer *bytes.Buffer, s string) {
+	buffer.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\b':
+			buffer.WriteString(`\b`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buffer.WriteString(`\u00`)
+				buffer.WriteByte(hexDigits[r>>4])
+				buffer.WriteByte(hexDigits[r&0xF])
+				continue
+			}
+			buffer.WriteRune(r)
+		}
+	}
+	buffer.WriteByte('"')
+}
+
+// compareUTF16 compares two strings by their UTF-16 code units
+//
+// Parameters:
+//
+//   - a: The first string
+//   - b: The second string
+//
+// Returns:
+//
+//   - int: A negative number if a < b, zero if a == b, a positive number if a > b
+func compareUTF16(a, b string) int {
+	return slices.Compare(
+		utf16.Encode([]rune(a)),
+		utf16.Encode([]rune(b)),
+	)
+}
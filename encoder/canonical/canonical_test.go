@@ -0,0 +1,159 @@
+package canonical
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "sorts object keys",
+			input: `{"b": 1, "a": 2, "c": {"z": true, "y": null}}`,
+			want:  `{"a":2,"b":1,"c":{"y":null,"z":true}}`,
+		},
+		{
+			name:  "sorts object keys by UTF-16 code units",
+			input: `{"\ud83d\ude00": 1, "\u20ac": 2, "\r": 3, "1": 4, "\u00f6": 5}`,
+			want:  "{\"\\r\":3,\"1\":4,\"\u00f6\":5,\"\u20ac\":2,\"\U0001F600\":1}",
+		},
+		{
+			name:  "keeps the array order",
+			input: `[3, 1, [2, {}], []]`,
+			want:  `[3,1,[2,{}],[]]`,
+		},
+		{
+			name:  "removes the insignificant whitespace",
+			input: " {\n\t\"a\" : [ 1 , 2 ] \r\n} ",
+			want:  `{"a":[1,2]}`,
+		},
+		{
+			name:  "escapes only the required characters",
+			input: `"\u0041\/\u001f\u007f\u2028\t\"\\"`,
+			want:  "\"A/\\u001f\u007f\u2028\\t\\\"\\\\\"",
+		},
+		{
+			name:  "serializes the negative zero as zero",
+			input: `-0.0`,
+			want:  `0`,
+		},
+		{
+			name:  "removes the trailing zeros of the fraction",
+			input: `[4.50, 2e-3, 1E2, 0.000001]`,
+			want:  `[4.5,0.002,100,0.000001]`,
+		},
+		{
+			name:  "uses the exponential notation below 1e-6",
+			input: `[1e-7, -1.5e-10]`,
+			want:  `[1e-7,-1.5e-10]`,
+		},
+		{
+			name:  "uses the exponential notation from 1e21",
+			input: `[1e20, 1e21, 123456789012345678901234]`,
+			want:  `[100000000000000000000,1e+21,1.2345678901234569e+23]`,
+		},
+		{
+			name:  "rounds to the nearest double",
+			input: `[9007199254740993, 0.1, 333333333.33333329]`,
+			want:  `[9007199254740992,0.1,333333333.3333333]`,
+		},
+		{
+			name:    "rejects the numbers out of the double range",
+			input:   `1e400`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects the duplicate keys",
+			input:   `{"a": 1, "a": 2}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects the nested duplicate keys",
+			input:   `[{"b": {"a": 1, "a": 1}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects the trailing data",
+			input:   `{} {}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects the invalid JSON",
+			input:   `{"a": }`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := Canonicalize([]byte(test.input))
+				if test.wantErr {
+					if err == nil {
+						t.Fatalf("Canonicalize(%q) = %q, want an error", test.input, got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Canonicalize(%q) error = %v", test.input, err)
+				}
+				if string(got) != test.want {
+					t.Errorf("Canonicalize(%q) = %q, want %q", test.input, got, test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestCanonicalizeTrailingData(t *testing.T) {
+	if _, err := Canonicalize([]byte(`1 2`)); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("Canonicalize error = %v, want %v", err, ErrTrailingData)
+	}
+}
+
+func TestEncoderEncode(t *testing.T) {
+	type nested struct {
+		Zeta  string  `json:"zeta"`
+		Alpha float64 `json:"alpha"`
+	}
+	tests := []struct {
+		name string
+		body any
+		want string
+	}{
+		{
+			name: "struct",
+			body: struct {
+				Name   string `json:"name"`
+				Nested nested `json:"nested"`
+				Count  int    `json:"count"`
+			}{Name: "n", Nested: nested{Zeta: "z", Alpha: 1e-7}, Count: 3},
+			want: `{"count":3,"name":"n","nested":{"alpha":1e-7,"zeta":"z"}}`,
+		},
+		{
+			name: "map",
+			body: map[string]any{"b": []int{2, 1}, "a": "\u00e9<>&"},
+			want: "{\"a\":\"\u00e9<>&\",\"b\":[2,1]}",
+		},
+	}
+
+	encoder := NewEncoder(nil)
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := encoder.Encode(test.body)
+				if err != nil {
+					t.Fatalf("Encode error = %v", err)
+				}
+				if string(got) != test.want {
+					t.Errorf("Encode = %s, want %s", got, test.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,198 @@
+package canonical
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+)
+
+type (
+	// contextProtoJSONEncoder is the interface implemented by the proto JSON encoders that stop precomputing the
+	// body once the context is done, such as the protojson encoder
+	contextProtoJSONEncoder interface {
+		PrecomputeMarshalContext(
+			ctx context.Context,
+			body any,
+		) (map[string]any, error)
+	}
+
+	// Encoder is the RFC 8785 JSON Canonicalization Scheme (JCS) implementation of the Encoder interface
+	Encoder struct {
+		protoJSONEncoder gojsonencoder.ProtoJSONEncoder
+	}
+
+	// Options are the additional settings for the encoder implementation
+	Options struct {
+		// protoJSONEncoder is used to precompute the body before canonicalizing it, so proto.Message fields are
+		// encoded with their protojson representation
+		protoJSONEncoder gojsonencoder.ProtoJSONEncoder
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - protoJSONEncoder: the encoder used to precompute the body before canonicalizing it (optional, can be nil)
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	protoJSONEncoder gojsonencoder.ProtoJSONEncoder,
+) *Options {
+	return &Options{
+		protoJSONEncoder: protoJSONEncoder,
+	}
+}
+
+// NewEncoder creates a new canonical JSON encoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the encoder implementation
+//
+// Returns:
+//
+//   - *Encoder: the new Encoder instance
+func NewEncoder(options *Options) *Encoder {
+	// Initialize the proto JSON encoder
+	var protoJSONEncoder gojsonencoder.ProtoJSONEncoder
+	if options != nil {
+		protoJSONEncoder = options.protoJSONEncoder
+	}
+
+	return &Encoder{
+		protoJSONEncoder: protoJSONEncoder,
+	}
+}
+
+// Encode encodes the body into canonical JSON bytes
+//
+// Parameters:
+//
+//   - body: The body to encode, it can also be the map[string]any returned by ProtoJSONEncoder.PrecomputeMarshal
+//
+// Returns:
+//
+//   - []byte: The canonical JSON bytes
+//   - error: The error if any
+func (e Encoder) Encode(
+	body any,
+) ([]byte, error) {
+	return e.encode(context.Background(), body)
+}
+
+// encode encodes the body into canonical JSON bytes, checking the context before each step
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - []byte: The canonical JSON bytes
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) encode(
+	ctx context.Context,
+	body any,
+) ([]byte, error) {
+	// Check if body is nil
+	if body == nil {
+		return nil, gojsonencoder.ErrNilBody
+	}
+
+	// Precompute the body if a proto JSON encoder is set
+	if e.protoJSONEncoder != nil {
+		var precomputedMarshal map[string]any
+		var err error
+		if contextEncoder, ok := e.protoJSONEncoder.(contextProtoJSONEncoder); ok {
+			precomputedMarshal, err = contextEncoder.PrecomputeMarshalContext(ctx, body)
+		} else {
+			precomputedMarshal, err = e.protoJSONEncoder.PrecomputeMarshal(body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		body = precomputedMarshal
+	}
+
+	// Check the context before marshaling the body
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	// Marshal the body into JSON, embedded json.RawMessage values are kept as they are
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Canonicalize the whole document, including the embedded raw fragments
+	return Canonicalize(jsonBody)
+}
+
+// EncodeAndWrite encodes the body into canonical JSON and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body into canonical JSON and writes it to the writer, nothing is written once
+// the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	// Check if the writer is nil
+	if writer == nil {
+		return gojsonencoder.ErrNilWriter
+	}
+
+	// Encode the body into canonical JSON
+	jsonBody, err := e.encode(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	// Call the before write function if provided, once the context is checked
+	beforeWriteFn = gojsonencoder.ContextBeforeWriteFn(ctx, beforeWriteFn)
+	if beforeWriteFn != nil {
+		if fnErr := beforeWriteFn(); fnErr != nil {
+			return fnErr
+		}
+	}
+
+	// Write the canonical JSON body to the writer
+	_, writeErr := writer.Write(jsonBody)
+	return writeErr
+}
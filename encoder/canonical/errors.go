@@ -0,0 +1,15 @@
+package canonical
+
+import (
+	"errors"
+)
+
+const (
+	ErrInvalidNumber   = "invalid number for canonical JSON: %s"
+	ErrDuplicateKey    = "duplicate object key for canonical JSON: %q"
+	ErrUnexpectedValue = "unexpected value type for canonical JSON: %T"
+)
+
+var (
+	ErrTrailingData = errors.New("unexpected data after the top-level JSON value")
+)
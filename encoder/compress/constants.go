@@ -0,0 +1,20 @@
+package compress
+
+type (
+	// Encoding is the content encoding used to compress the body
+	Encoding string
+)
+
+const (
+	// EncodingGzip compresses the body with compress/gzip
+	EncodingGzip Encoding = "gzip"
+
+	// EncodingDeflate compresses the body with compress/zlib, as the HTTP deflate content encoding is the zlib format
+	// of RFC 1950 wrapping the raw deflate stream
+	EncodingDeflate Encoding = "deflate"
+)
+
+const (
+	// DefaultMinSize is the default minimum size in bytes of a body to be compressed
+	DefaultMinSize = 1024
+)
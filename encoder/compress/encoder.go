@@ -0,0 +1,258 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+)
+
+type (
+	// Encoder wraps another encoder and compresses its output, it implements the Encoder interface so any encoder
+	// can opt into the compression
+	Encoder struct {
+		encoder     gojsonencoder.Encoder
+		encoding    Encoding
+		minSize     int
+		compressors *sync.Pool
+	}
+
+	// Options are the additional settings for the encoder implementation
+	Options struct {
+		// encoding is the content encoding used to compress the body
+		encoding Encoding
+
+		// level is the compression level
+		level int
+
+		// minSize is the minimum size in bytes of a body to be compressed by EncodeAndWrite
+		minSize int
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - encoding: the content encoding used to compress the body
+//   - level: the compression level, as defined by compress/flate
+//   - minSize: the minimum size in bytes of a body to be compressed by EncodeAndWrite, smaller bodies are written
+//     uncompressed
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	encoding Encoding,
+	level int,
+	minSize int,
+) *Options {
+	return &Options{
+		encoding: encoding,
+		level:    level,
+		minSize:  minSize,
+	}
+}
+
+// NewEncoder creates a new compressing encoder
+//
+// Parameters:
+//
+//   - encoder: the encoder whose output is compressed
+//   - options: the additional settings for the encoder implementation, nil uses gzip with the default compression
+//     level and DefaultMinSize
+//
+// Returns:
+//
+//   - *Encoder: the new Encoder instance
+//   - error: the error if the encoder is nil, or the encoding or the level are not supported
+func NewEncoder(
+	encoder gojsonencoder.Encoder,
+	options *Options,
+) (*Encoder, error) {
+	// Check if the encoder is nil
+	if encoder == nil {
+		return nil, gojsonencoder.ErrNilEncoder
+	}
+
+	// Initialize the options
+	if options == nil {
+		options = NewOptions(EncodingGzip, gzip.DefaultCompression, DefaultMinSize)
+	}
+
+	// Initialize the compressor pool
+	var newCompressor func() (compressor, error)
+	switch options.encoding {
+	case EncodingGzip:
+		newCompressor = func() (compressor, error) {
+			return gzip.NewWriterLevel(io.Discard, options.level)
+		}
+	case EncodingDeflate:
+		newCompressor = func() (compressor, error) {
+			return zlib.NewWriterLevel(io.Discard, options.level)
+		}
+	default:
+		return nil, fmt.Errorf(ErrUnsupportedEncoding, options.encoding)
+	}
+
+	// Check the level by creating the first compressor
+	firstCompressor, err := newCompressor()
+	if err != nil {
+		return nil, err
+	}
+	compressors := &sync.Pool{
+		New: func() any {
+			// The level was already checked, so the error can be ignored
+			newCompressorInstance, _ := newCompressor()
+			return newCompressorInstance
+		},
+	}
+	compressors.Put(firstCompressor)
+
+	return &Encoder{
+		encoder:     encoder,
+		encoding:    options.encoding,
+		minSize:     options.minSize,
+		compressors: compressors,
+	}, nil
+}
+
+// Encoding returns the content encoding used to compress the body
+//
+// Returns:
+//
+//   - Encoding: the content encoding
+func (e Encoder) Encoding() Encoding {
+	return e.encoding
+}
+
+// getCompressor gets a compressor from the pool and resets it to write to the writer
+//
+// Parameters:
+//
+//   - writer: the writer to write the compressed bytes to
+//
+// Returns:
+//
+//   - compressor: the compressor
+func (e Encoder) getCompressor(writer io.Writer) compressor {
+	pooledCompressor, _ := e.compressors.Get().(compressor)
+	pooledCompressor.Reset(writer)
+	return pooledCompressor
+}
+
+// putCompressor returns the compressor to the pool
+//
+// Parameters:
+//
+//   - pooledCompressor: the compressor to return
+func (e Encoder) putCompressor(pooledCompressor compressor) {
+	pooledCompressor.Reset(io.Discard)
+	e.compressors.Put(pooledCompressor)
+}
+
+// Encode encodes the body with the wrapped encoder and compresses it, regardless of its size
+//
+// Parameters:
+//
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - []byte: The compressed encoded body
+//   - error: The error if any
+func (e Encoder) Encode(
+	body any,
+) ([]byte, error) {
+	// Encode the body
+	encodedBody, err := e.encoder.Encode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compress it into a pooled buffer
+	buffer := gojsonbuffer.Get(len(encodedBody))
+	defer gojsonbuffer.Put(buffer)
+
+	pooledCompressor := e.getCompressor(buffer)
+	defer e.putCompressor(pooledCompressor)
+	if _, err = pooledCompressor.Write(encodedBody); err != nil {
+		return nil, err
+	}
+	if err = pooledCompressor.Close(); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buffer.Bytes()...), nil
+}
+
+// EncodeAndWrite encodes the body with the wrapped encoder and writes it to the writer, compressed if it reaches
+// the minimum size. When compressed and the writer is an http.ResponseWriter, the Content-Encoding header is set
+// before calling the before write function
+//
+// Parameters:
+//
+//   - writer: The writer to write the body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body with the wrapped encoder and writes it to the writer as EncodeAndWrite
+// does, until the context is done. The wrapped encoder is called through EncodeAndWriteContext
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	// Check if the writer is nil
+	if writer == nil {
+		return gojsonencoder.ErrNilWriter
+	}
+
+	// Encode the body through the threshold writer, it calls the before write function itself
+	thresholdWriter := newThresholdWriter(&e, writer, beforeWriteFn)
+	if err := gojsonencoder.EncodeAndWriteContext(ctx, e.encoder, thresholdWriter, nil, body); err != nil {
+		thresholdWriter.release()
+		return err
+	}
+	return thresholdWriter.Close()
+}
+
+// ContentType returns the media type of the wrapped encoder, the compression is signaled by the Content-Encoding
+// header instead
+//
+// Returns:
+//
+//   - string: the media type, empty if the wrapped encoder does not define one
+func (e Encoder) ContentType() string {
+	if contentTypeEncoder, ok := e.encoder.(gojsonencoder.ContentTypeEncoder); ok {
+		return contentTypeEncoder.ContentType()
+	}
+	return ""
+}
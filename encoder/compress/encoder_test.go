@@ -0,0 +1,121 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+func TestEncoderEncodeAndWrite(t *testing.T) {
+	tests := []struct {
+		name         string
+		encoding     Encoding
+		body         string
+		wantEncoding string
+	}{
+		{
+			name:         "compresses with gzip",
+			encoding:     EncodingGzip,
+			body:         strings.Repeat("x", 2048),
+			wantEncoding: "gzip",
+		},
+		{
+			name:         "compresses with the zlib format for deflate",
+			encoding:     EncodingDeflate,
+			body:         strings.Repeat("x", 2048),
+			wantEncoding: "deflate",
+		},
+		{
+			name:     "writes the small bodies uncompressed",
+			encoding: EncodingGzip,
+			body:     "small",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				encoder, err := NewEncoder(
+					gojsonencoderjson.NewEncoder(nil),
+					NewOptions(test.encoding, gzip.DefaultCompression, DefaultMinSize),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				recorder := httptest.NewRecorder()
+				if err = encoder.EncodeAndWrite(recorder, nil, test.body); err != nil {
+					t.Fatal(err)
+				}
+
+				// Check the headers, the response varies with the accepted encodings even if it is not compressed
+				if got := recorder.Header().Get("Content-Encoding"); got != test.wantEncoding {
+					t.Errorf("Content-Encoding = %q, want %q", got, test.wantEncoding)
+				}
+				if got := recorder.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+					t.Errorf("Vary = %q, want [\"Accept-Encoding\"]", got)
+				}
+
+				// Check the decompressed body
+				var reader io.Reader = recorder.Body
+				switch test.wantEncoding {
+				case "gzip":
+					reader, err = gzip.NewReader(reader)
+				case "deflate":
+					reader, err = zlib.NewReader(reader)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := "\"" + test.body + "\""; string(got) != want {
+					t.Errorf("body = %.32q, want %.32q", got, want)
+				}
+			},
+		)
+	}
+}
+
+func TestThresholdWriterFlush(t *testing.T) {
+	encoder, err := NewEncoder(gojsonencoderjson.NewEncoder(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	writer := newThresholdWriter(encoder, recorder, nil)
+
+	// Flushing the buffered bytes starts the compression and flushes the response
+	if _, err = writer.Write([]byte("{\"id\":1}")); err != nil {
+		t.Fatal(err)
+	}
+	writer.Flush()
+	if !recorder.Flushed {
+		t.Error("response was not flushed")
+	}
+	if got := recorder.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want \"gzip\"", got)
+	}
+
+	// The flushed bytes can be decompressed before the stream is closed
+	reader, err := gzip.NewReader(recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 8)
+	if _, err = io.ReadFull(reader, got); err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "{\"id\":1}" {
+		t.Errorf("flushed body = %q, want {\"id\":1}", got)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
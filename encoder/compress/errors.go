@@ -0,0 +1,5 @@
+package compress
+
+const (
+	ErrUnsupportedEncoding = "unsupported content encoding: %s"
+)
@@ -0,0 +1,211 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+)
+
+type (
+	// compressor is the writer implemented by both gzip.Writer and zlib.Writer
+	compressor interface {
+		io.WriteCloser
+		Flush() error
+		Reset(writer io.Writer)
+	}
+
+	// thresholdWriter buffers the written bytes until the minimum size is reached, then it starts compressing them.
+	// If the minimum size is never reached, the bytes are written uncompressed on Close. It implements http.Flusher,
+	// so the streaming encoders can flush the compressed bytes
+	thresholdWriter struct {
+		encoder       *Encoder
+		writer        io.Writer
+		beforeWriteFn func() error
+		buffer        *bytes.Buffer
+		compressor    compressor
+		err           error
+	}
+)
+
+// newThresholdWriter creates a new thresholdWriter instance
+//
+// Parameters:
+//
+//   - encoder: the compressing encoder that holds the settings
+//   - writer: the underlying writer
+//   - beforeWriteFn: the function to call before writing the first byte to the underlying writer
+//
+// Returns:
+//
+//   - *thresholdWriter: the new thresholdWriter instance
+func newThresholdWriter(
+	encoder *Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+) *thresholdWriter {
+	return &thresholdWriter{
+		encoder:       encoder,
+		writer:        writer,
+		beforeWriteFn: beforeWriteFn,
+		buffer:        gojsonbuffer.Get(encoder.minSize),
+	}
+}
+
+// Write buffers or compresses the given bytes
+//
+// Parameters:
+//
+//   - p: the bytes to write
+//
+// Returns:
+//
+//   - int: the number of bytes written
+//   - error: the error if any
+func (t *thresholdWriter) Write(p []byte) (int, error) {
+	// Check the error of a previous flush
+	if t.err != nil {
+		return 0, t.err
+	}
+
+	// Compress the bytes if the compression already started
+	if t.compressor != nil {
+		return t.compressor.Write(p)
+	}
+
+	// Buffer the bytes until the minimum size is reached
+	n, _ := t.buffer.Write(p)
+	if t.buffer.Len() < t.encoder.minSize {
+		return n, nil
+	}
+	if err := t.startCompression(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// startCompression sets the content encoding, calls the before write function and starts the compression with the
+// buffered bytes
+//
+// Returns:
+//
+//   - error: the error if any
+func (t *thresholdWriter) startCompression() error {
+	setHeaders(t.writer, t.encoder.encoding)
+	if t.beforeWriteFn != nil {
+		if err := t.beforeWriteFn(); err != nil {
+			return err
+		}
+	}
+	t.compressor = t.encoder.getCompressor(t.writer)
+	if _, err := t.compressor.Write(t.buffer.Bytes()); err != nil {
+		return err
+	}
+	t.buffer.Reset()
+	return nil
+}
+
+// Flush writes the pending compressed bytes and flushes the underlying writer if it is an http.Flusher. The bytes
+// buffered before the minimum size is reached start the compression, as they cannot be sent uncompressed once more
+// bytes may follow. A flush error is returned by the next Write or Close
+func (t *thresholdWriter) Flush() {
+	if t.err != nil {
+		return
+	}
+
+	// Nothing to send yet
+	if t.compressor == nil {
+		if t.buffer.Len() == 0 {
+			return
+		}
+		if t.err = t.startCompression(); t.err != nil {
+			return
+		}
+	}
+
+	// Flush the compressor and the underlying writer
+	if t.err = t.compressor.Flush(); t.err != nil {
+		return
+	}
+	if flusher, ok := t.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close flushes the compressed bytes, or writes the buffered bytes uncompressed if the minimum size was not reached
+//
+// Returns:
+//
+//   - error: the error if any
+func (t *thresholdWriter) Close() error {
+	defer t.release()
+
+	// Check the error of a previous flush
+	if t.err != nil {
+		return t.err
+	}
+
+	// Finish the compression
+	if t.compressor != nil {
+		return t.compressor.Close()
+	}
+
+	// Write the buffered bytes uncompressed, the response still varies with the accepted encodings
+	if responseWriter, ok := t.writer.(http.ResponseWriter); ok {
+		addVary(responseWriter.Header())
+	}
+	if t.beforeWriteFn != nil {
+		if err := t.beforeWriteFn(); err != nil {
+			return err
+		}
+	}
+	_, err := t.writer.Write(t.buffer.Bytes())
+	return err
+}
+
+// release returns the buffer and the compressor to their pools
+func (t *thresholdWriter) release() {
+	if t.buffer != nil {
+		gojsonbuffer.Put(t.buffer)
+		t.buffer = nil
+	}
+	if t.compressor != nil {
+		t.encoder.putCompressor(t.compressor)
+		t.compressor = nil
+	}
+}
+
+// setHeaders sets the content encoding headers if the writer is an http.ResponseWriter
+//
+// Parameters:
+//
+//   - writer: the writer
+//   - encoding: the content encoding
+func setHeaders(writer io.Writer, encoding Encoding) {
+	responseWriter, ok := writer.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	header := responseWriter.Header()
+	header.Set("Content-Encoding", string(encoding))
+	header.Del("Content-Length")
+	addVary(header)
+}
+
+// addVary adds Accept-Encoding to the Vary header, unless it is already listed
+//
+// Parameters:
+//
+//   - header: the response header
+func addVary(header http.Header) {
+	for _, value := range header.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), "Accept-Encoding") {
+				return
+			}
+		}
+	}
+	header.Add("Vary", "Accept-Encoding")
+}
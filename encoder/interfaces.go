@@ -1,6 +1,7 @@
 package encoder
 
 import (
+	"context"
 	"io"
 )
 
@@ -17,6 +18,34 @@ type (
 		) error
 	}
 
+	// ContextEncoder is the interface implemented by the encoders that stop encoding and writing once the context is
+	// done
+	ContextEncoder interface {
+		Encoder
+		EncodeAndWriteContext(
+			ctx context.Context,
+			writer io.Writer,
+			beforeWriteFn func() error,
+			body any,
+		) error
+	}
+
+	// AppendEncoder is the interface implemented by the encoders that can append the encoded body to a
+	// caller-provided buffer
+	AppendEncoder interface {
+		Encoder
+		EncodeTo(
+			dst []byte,
+			body any,
+		) ([]byte, error)
+	}
+
+	// ContentTypeEncoder is the interface implemented by the encoders whose output is not application/json
+	ContentTypeEncoder interface {
+		Encoder
+		ContentType() string
+	}
+
 	// ProtoJSONEncoder interface
 	ProtoJSONEncoder interface {
 		Encoder
@@ -0,0 +1,326 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+)
+
+type (
+	// ArrayEncoder is the encoder that streams a top-level JSON array, each element is encoded with the element
+	// encoder and written as soon as it is pulled, without materializing the whole slice
+	ArrayEncoder struct {
+		elementEncoder gojsonencoder.Encoder
+		trailer        Trailer
+	}
+
+	// ArrayOptions are the additional settings for the array encoder implementation
+	ArrayOptions struct {
+		// elementEncoder is the encoder used to encode each element
+		elementEncoder gojsonencoder.Encoder
+
+		// trailer writes the end of the array when the iteration fails midway
+		trailer Trailer
+	}
+)
+
+// NewArrayOptions creates a new ArrayOptions instance
+//
+// Parameters:
+//
+//   - elementEncoder: the encoder used to encode each element, nil uses the default JSON encoder
+//   - trailer: writes the end of the array when the iteration fails midway, nil leaves the array unterminated
+//
+// Returns:
+//
+//   - *ArrayOptions: the new ArrayOptions instance
+func NewArrayOptions(
+	elementEncoder gojsonencoder.Encoder,
+	trailer Trailer,
+) *ArrayOptions {
+	return &ArrayOptions{
+		elementEncoder: elementEncoder,
+		trailer:        trailer,
+	}
+}
+
+// NewArrayEncoder creates a new JSON array encoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the array encoder implementation
+//
+// Returns:
+//
+//   - *ArrayEncoder: the new ArrayEncoder instance
+func NewArrayEncoder(options *ArrayOptions) *ArrayEncoder {
+	// Initialize the settings
+	var elementEncoder gojsonencoder.Encoder
+	var trailer Trailer
+	if options != nil {
+		elementEncoder = options.elementEncoder
+		trailer = options.trailer
+	}
+
+	// Initialize the element encoder
+	if elementEncoder == nil {
+		elementEncoder = NewEncoder(nil)
+	}
+
+	return &ArrayEncoder{
+		elementEncoder: elementEncoder,
+		trailer:        trailer,
+	}
+}
+
+// WriteArraySeq2 pulls each element from the sequence, encodes it and writes it to the writer as part of a JSON
+// array, so it can be fed from a fallible source such as a database cursor
+//
+// The opening bracket is only written with the first element or once the sequence ends, so if the sequence fails
+// before any element nothing is written and the error is returned. If it fails midway, the trailer writes the end
+// of the array and the error is returned
+//
+// Parameters:
+//
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - seq: The sequence of elements and iteration errors
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteArraySeq2[T any](
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq2[T, error],
+) error {
+	return WriteArraySeq2Context(context.Background(), encoder, writer, beforeWriteFn, seq)
+}
+
+// WriteArraySeq2Context pulls each element from the sequence, encodes it and writes it to the writer as part of a
+// JSON array, as WriteArraySeq2 does, until the context is done. The context is checked before each element and
+// handled as an iteration failure, so the trailer writes the end of the array once it is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - seq: The sequence of elements and iteration errors
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func WriteArraySeq2Context[T any](
+	ctx context.Context,
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq2[T, error],
+) error {
+	// Check if the writer is nil
+	if writer == nil {
+		return gojsonencoder.ErrNilWriter
+	}
+
+	// Initialize the encoder
+	if encoder == nil {
+		encoder = NewArrayEncoder(nil)
+	}
+
+	// Write the opening bracket once, after calling the before write function
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		if beforeWriteFn != nil {
+			if err := beforeWriteFn(); err != nil {
+				return err
+			}
+		}
+		_, err := writer.Write([]byte{'['})
+		return err
+	}
+
+	// Encode and write each element
+	var iterErr error
+	index := 0
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	for element, err := range seq {
+		if err != nil {
+			iterErr = err
+			break
+		}
+
+		// Check the context
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			iterErr = ctxErr
+			break
+		}
+
+		// Encode the element
+		data, encodeErr := encoder.elementEncoder.Encode(element)
+		if encodeErr != nil {
+			iterErr = fmt.Errorf(ErrEncodeArrayElement, index, encodeErr)
+			break
+		}
+
+		// Write the element preceded by the opening bracket or the separator
+		if startErr := start(); startErr != nil {
+			return startErr
+		}
+		buffer.Reset()
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.Write(bytes.TrimRight(data, "\n"))
+		if _, writeErr := writer.Write(buffer.Bytes()); writeErr != nil {
+			return writeErr
+		}
+		index++
+	}
+
+	// Check the context once the sequence ends, as it may have stopped without yielding an element
+	if iterErr == nil {
+		iterErr = context.Cause(ctx)
+	}
+
+	// Handle the iteration failure
+	if iterErr != nil {
+		if !started || encoder.trailer == nil {
+			return iterErr
+		}
+		if trailerErr := encoder.trailer.WriteTrailer(writer, index > 0, iterErr); trailerErr != nil {
+			return errors.Join(iterErr, trailerErr)
+		}
+		return iterErr
+	}
+
+	// Write the closing bracket
+	if startErr := start(); startErr != nil {
+		return startErr
+	}
+	_, err := writer.Write([]byte{']'})
+	return err
+}
+
+// WriteArraySeq pulls each element from the sequence, encodes it and writes it to the writer as part of a JSON array
+//
+// Parameters:
+//
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - seq: The sequence of elements
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteArraySeq[T any](
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq[T],
+) error {
+	return WriteArraySeqContext(context.Background(), encoder, writer, beforeWriteFn, seq)
+}
+
+// WriteArraySeqContext pulls each element from the sequence, encodes it and writes it to the writer as part of a
+// JSON array, until the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - seq: The sequence of elements
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func WriteArraySeqContext[T any](
+	ctx context.Context,
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq[T],
+) error {
+	return WriteArraySeq2Context(ctx, encoder, writer, beforeWriteFn, func(yield func(T, error) bool) {
+		for element := range seq {
+			if !yield(element, nil) {
+				return
+			}
+		}
+	})
+}
+
+// WriteArrayChan receives each element from the channel, encodes it and writes it to the writer as part of a JSON
+// array, until the channel is closed
+//
+// Parameters:
+//
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - ch: The channel of elements
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteArrayChan[T any](
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	ch <-chan T,
+) error {
+	return WriteArrayChanContext(context.Background(), encoder, writer, beforeWriteFn, ch)
+}
+
+// WriteArrayChanContext receives each element from the channel, encodes it and writes it to the writer as part of a
+// JSON array, until the channel is closed or the context is done. A receive blocked on the channel is also
+// interrupted once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The JSON array encoder, nil uses the default encoder
+//   - writer: The writer to write the array to
+//   - beforeWriteFn: The function to call once before writing the first byte
+//   - ch: The channel of elements
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func WriteArrayChanContext[T any](
+	ctx context.Context,
+	encoder *ArrayEncoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	ch <-chan T,
+) error {
+	return WriteArraySeq2Context(ctx, encoder, writer, beforeWriteFn, func(yield func(T, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case element, ok := <-ch:
+				if !ok || !yield(element, nil) {
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"testing"
+)
+
+// seq2Of returns a sequence of the elements that fails with the error after them, if any
+func seq2Of(err error, elements ...int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for _, element := range elements {
+			if !yield(element, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(0, err)
+		}
+	}
+}
+
+func TestWriteArraySeq2(t *testing.T) {
+	iterErr := errors.New("cursor: connection to 10.0.0.1 lost")
+	hideMessage := func(error) string { return "internal error" }
+
+	tests := []struct {
+		name        string
+		seq         iter.Seq2[int, error]
+		newTrailer  func(header http.Header) Trailer
+		want        string
+		wantErr     error
+		wantStarted bool
+		wantTrailer string
+	}{
+		{
+			name:        "writes the empty sequence as an empty array",
+			seq:         seq2Of(nil),
+			want:        "[]",
+			wantStarted: true,
+		},
+		{
+			name:        "writes the elements",
+			seq:         seq2Of(nil, 1, 2, 3),
+			want:        "[1,2,3]",
+			wantStarted: true,
+		},
+		{
+			name: "writes nothing when the sequence fails before the first element",
+			seq:  seq2Of(iterErr),
+			newTrailer: func(http.Header) Trailer {
+				return NewErrorObjectTrailer("error", nil)
+			},
+			wantErr: iterErr,
+		},
+		{
+			name:        "leaves the array unterminated without a trailer",
+			seq:         seq2Of(iterErr, 1, 2),
+			want:        "[1,2",
+			wantErr:     iterErr,
+			wantStarted: true,
+		},
+		{
+			name: "appends the error object when the sequence fails midway",
+			seq:  seq2Of(iterErr, 1, 2),
+			newTrailer: func(http.Header) Trailer {
+				return NewErrorObjectTrailer("error", nil)
+			},
+			want:        `[1,2,{"error":"cursor: connection to 10.0.0.1 lost"}]`,
+			wantErr:     iterErr,
+			wantStarted: true,
+		},
+		{
+			name: "appends the mapped error message when the sequence fails midway",
+			seq:  seq2Of(iterErr, 1),
+			newTrailer: func(http.Header) Trailer {
+				return NewErrorObjectTrailer("error", hideMessage)
+			},
+			want:        `[1,{"error":"internal error"}]`,
+			wantErr:     iterErr,
+			wantStarted: true,
+		},
+		{
+			name: "sets the HTTP trailer when the sequence fails midway",
+			seq:  seq2Of(iterErr, 1, 2),
+			newTrailer: func(header http.Header) Trailer {
+				return NewHTTPTrailer(header, "X-Error", hideMessage)
+			},
+			want:        "[1,2]",
+			wantErr:     iterErr,
+			wantStarted: true,
+			wantTrailer: "internal error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				header := make(http.Header)
+				var trailer Trailer
+				if test.newTrailer != nil {
+					trailer = test.newTrailer(header)
+				}
+				started := false
+				var buffer bytes.Buffer
+				err := WriteArraySeq2(
+					NewArrayEncoder(NewArrayOptions(nil, trailer)),
+					&buffer,
+					func() error {
+						started = true
+						return nil
+					},
+					test.seq,
+				)
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("error = %v, want %v", err, test.wantErr)
+				}
+				if buffer.String() != test.want {
+					t.Errorf("written = %q, want %q", buffer.String(), test.want)
+				}
+				if started != test.wantStarted {
+					t.Errorf("before write function called = %t, want %t", started, test.wantStarted)
+				}
+				if got := header.Get("X-Error"); got != test.wantTrailer {
+					t.Errorf("trailer = %q, want %q", got, test.wantTrailer)
+				}
+			},
+		)
+	}
+}
+
+func TestWriteArraySeq(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []string
+		want     string
+	}{
+		{
+			name: "writes the empty sequence as an empty array",
+			want: "[]",
+		},
+		{
+			name:     "writes the elements",
+			elements: []string{"a", "b"},
+			want:     `["a","b"]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var buffer bytes.Buffer
+				seq := func(yield func(string) bool) {
+					for _, element := range test.elements {
+						if !yield(element) {
+							return
+						}
+					}
+				}
+				if err := WriteArraySeq(nil, &buffer, nil, seq); err != nil {
+					t.Fatal(err)
+				}
+				if buffer.String() != test.want {
+					t.Errorf("written = %q, want %q", buffer.String(), test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestWriteArrayChan(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []int
+		want     string
+	}{
+		{
+			name: "writes the closed empty channel as an empty array",
+			want: "[]",
+		},
+		{
+			name:     "writes the elements until the channel is closed",
+			elements: []int{1, 2},
+			want:     "[1,2]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				ch := make(chan int, len(test.elements))
+				for _, element := range test.elements {
+					ch <- element
+				}
+				close(ch)
+
+				var buffer bytes.Buffer
+				if err := WriteArrayChan(nil, &buffer, nil, ch); err != nil {
+					t.Fatal(err)
+				}
+				if buffer.String() != test.want {
+					t.Errorf("written = %q, want %q", buffer.String(), test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestWriteArrayChanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+
+	// Cancel the context once the elements are written, the receive blocked on the open channel is interrupted and
+	// the trailer ends the array
+	var buffer bytes.Buffer
+	writer := writerFunc(
+		func(p []byte) (int, error) {
+			n, err := buffer.Write(p)
+			if bytes.HasSuffix(buffer.Bytes(), []byte(",2")) {
+				cancel()
+			}
+			return n, err
+		},
+	)
+	encoder := NewArrayEncoder(NewArrayOptions(nil, NewErrorObjectTrailer("error", nil)))
+	err := WriteArrayChanContext(ctx, encoder, writer, nil, ch)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+	if want := `[1,2,{"error":"context canceled"}]`; buffer.String() != want {
+		t.Errorf("written = %q, want %q", buffer.String(), want)
+	}
+}
+
+// writerFunc is a writer implemented by a function
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
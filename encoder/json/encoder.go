@@ -1,7 +1,7 @@
 package json
 
 import (
-	"encoding/json"
+	"context"
 	"io"
 
 	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
@@ -9,16 +9,29 @@ import (
 
 type (
 	// Encoder struct
-	Encoder struct{}
+	Encoder struct {
+		options Options
+	}
 )
 
-// NewEncoder creates a new default JSON encoder
+// NewEncoder creates a new JSON encoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the encoder implementation, nil uses the default options
 //
 // Returns:
 //
-//   - *Encoder: The default encoder
-func NewEncoder() *Encoder {
-	return &Encoder{}
+//   - *Encoder: The encoder
+func NewEncoder(options *Options) *Encoder {
+	// Initialize the options
+	if options == nil {
+		options = NewDefaultOptions()
+	}
+
+	return &Encoder{
+		options: *options,
+	}
 }
 
 // Encode encodes the body into JSON bytes
@@ -39,8 +52,8 @@ func (e Encoder) Encode(
 		return nil, gojsonencoder.ErrNilBody
 	}
 
-	// Marshal the body into JSON
-	jsonBody, err := json.Marshal(body)
+	// Marshal the body into JSON, without trailing newline by default
+	jsonBody, err := e.options.encode(body, false)
 	if err != nil {
 		return nil, err
 	}
@@ -62,26 +75,66 @@ func (e Encoder) EncodeAndWrite(
 	writer io.Writer,
 	beforeWriteFn func() error,
 	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body and writes it to the writer, nothing is written once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the response to
+//   - beforeWriteFn: The function to call before writing the response
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
 ) error {
 	// Check if the writer is nil
 	if writer == nil {
 		return gojsonencoder.ErrNilWriter
 	}
 
-	// Encode the body into JSON
-	jsonBody, err := e.Encode(body)
-	if err != nil {
+	// Check if body is nil
+	if body == nil {
+		return gojsonencoder.ErrNilBody
+	}
+
+	// Check the context before encoding
+	if err := context.Cause(ctx); err != nil {
 		return err
 	}
 
-	// Call the before write function if provided
-	if beforeWriteFn != nil {
-		if fnErr := beforeWriteFn(); fnErr != nil {
-			return fnErr
-		}
+	// Encode the body into a pooled buffer and write it to the writer, checking the context again before writing
+	return e.options.write(writer, gojsonencoder.ContextBeforeWriteFn(ctx, beforeWriteFn), body, false)
+}
+
+// EncodeTo encodes the body and appends it to the destination, so the caller can reuse its buffer
+//
+// Parameters:
+//
+//   - dst: The destination to append the encoded body to
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - []byte: The destination with the encoded JSON bytes appended, or the unchanged destination on error
+//   - error: The error if any
+func (e Encoder) EncodeTo(
+	dst []byte,
+	body any,
+) ([]byte, error) {
+	// Check if body is nil
+	if body == nil {
+		return dst, gojsonencoder.ErrNilBody
 	}
 
-	// Write the JSON body to the writer
-	_, writeErr := writer.Write(jsonBody)
-	return writeErr
+	return e.options.appendTo(dst, body, false)
 }
@@ -0,0 +1,195 @@
+package json
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type benchmarkItem struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Price float64  `json:"price"`
+}
+
+// benchmarkBody returns a body of the given number of items
+func benchmarkBody(items int) []benchmarkItem {
+	body := make([]benchmarkItem, items)
+	for i := range body {
+		body[i] = benchmarkItem{
+			ID:    i,
+			Name:  strings.Repeat("item", 4),
+			Tags:  []string{"a", "b", "c"},
+			Price: float64(i) * 1.5,
+		}
+	}
+	return body
+}
+
+func BenchmarkEncoderEncode(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		options *Options
+		items   int
+	}{
+		{name: "small", items: 1},
+		{name: "large", items: 1000},
+		{name: "large sorted", options: NewOptions("", "", true, TrailingNewlineDefault, KeyOrderSorted), items: 1000},
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(
+			benchmark.name, func(b *testing.B) {
+				encoder := NewEncoder(benchmark.options)
+				body := benchmarkBody(benchmark.items)
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := encoder.Encode(body); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkEncoderEncodeAndWrite(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		items int
+	}{
+		{name: "small", items: 1},
+		{name: "large", items: 1000},
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(
+			benchmark.name, func(b *testing.B) {
+				encoder := NewEncoder(nil)
+				body := benchmarkBody(benchmark.items)
+				b.ReportAllocs()
+				for b.Loop() {
+					if err := encoder.EncodeAndWrite(io.Discard, nil, body); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkStreamEncoderEncodeAndWrite(b *testing.B) {
+	encoder := NewStreamEncoder(nil)
+	body := benchmarkBody(1000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := encoder.EncodeAndWrite(io.Discard, nil, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncoderEncodeTo(b *testing.B) {
+	encoder := NewEncoder(nil)
+	body := benchmarkBody(1000)
+	var dst []byte
+	b.ReportAllocs()
+	for b.Loop() {
+		var err error
+		if dst, err = encoder.EncodeTo(dst[:0], body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type optionsBody struct {
+	Name  string         `json:"name"`
+	ID    uint64         `json:"id"`
+	Attrs map[string]int `json:"attrs"`
+}
+
+func TestEncoderOptions(t *testing.T) {
+	body := optionsBody{Name: "<a&b>", ID: 18446744073709551615, Attrs: map[string]int{"b": 2, "a": 1}}
+
+	tests := []struct {
+		name       string
+		options    *Options
+		want       string
+		wantStream string
+	}{
+		{
+			name:       "matches the encoding/json defaults",
+			want:       `{"name":"\u003ca\u0026b\u003e","id":18446744073709551615,"attrs":{"a":1,"b":2}}`,
+			wantStream: `{"name":"\u003ca\u0026b\u003e","id":18446744073709551615,"attrs":{"a":1,"b":2}}` + "\n",
+		},
+		{
+			name:       "does not escape the HTML characters",
+			options:    NewOptions("", "", false, TrailingNewlineDefault, KeyOrderDefault),
+			want:       `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}`,
+			wantStream: `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}` + "\n",
+		},
+		{
+			name:    "indents with the prefix and the indentation",
+			options: NewOptions("> ", "  ", false, TrailingNewlineDefault, KeyOrderDefault),
+			want: "{\n" +
+				`>   "name": "<a&b>",` + "\n" +
+				`>   "id": 18446744073709551615,` + "\n" +
+				`>   "attrs": {` + "\n" +
+				`>     "a": 1,` + "\n" +
+				`>     "b": 2` + "\n" +
+				`>   }` + "\n" +
+				"> }",
+			wantStream: "{\n" +
+				`>   "name": "<a&b>",` + "\n" +
+				`>   "id": 18446744073709551615,` + "\n" +
+				`>   "attrs": {` + "\n" +
+				`>     "a": 1,` + "\n" +
+				`>     "b": 2` + "\n" +
+				`>   }` + "\n" +
+				"> }\n",
+		},
+		{
+			name:       "always appends the trailing newline",
+			options:    NewOptions("", "", false, TrailingNewlineAlways, KeyOrderDefault),
+			want:       `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}` + "\n",
+			wantStream: `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}` + "\n",
+		},
+		{
+			name:       "never appends the trailing newline",
+			options:    NewOptions("", "", false, TrailingNewlineNever, KeyOrderDefault),
+			want:       `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}`,
+			wantStream: `{"name":"<a&b>","id":18446744073709551615,"attrs":{"a":1,"b":2}}`,
+		},
+		{
+			name:       "sorts the keys of the struct fields keeping the numbers and the HTML escaping",
+			options:    NewOptions("", "", true, TrailingNewlineDefault, KeyOrderSorted),
+			want:       `{"attrs":{"a":1,"b":2},"id":18446744073709551615,"name":"\u003ca\u0026b\u003e"}`,
+			wantStream: `{"attrs":{"a":1,"b":2},"id":18446744073709551615,"name":"\u003ca\u0026b\u003e"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := NewEncoder(test.options).Encode(body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != test.want {
+					t.Errorf("encoded = %q, want %q", got, test.want)
+				}
+
+				// The stream encoder appends the trailing newline by default
+				var buffer bytes.Buffer
+				if err = NewStreamEncoder(test.options).EncodeAndWrite(&buffer, nil, body); err != nil {
+					t.Fatal(err)
+				}
+				if buffer.String() != test.wantStream {
+					t.Errorf("stream encoded = %q, want %q", buffer.String(), test.wantStream)
+				}
+			},
+		)
+	}
+}
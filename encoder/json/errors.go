@@ -0,0 +1,5 @@
+package json
+
+const (
+	ErrEncodeArrayElement = "failed to encode array element %d: %w"
+)
@@ -0,0 +1,285 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+)
+
+type (
+	// TrailingNewline is the policy for the newline appended after the encoded body
+	TrailingNewline int
+
+	// KeyOrder is the policy for the order of the object keys in the encoded body
+	KeyOrder int
+
+	// Options are the additional settings for the JSON encoder implementations
+	Options struct {
+		// prefix is the prefix added to each indented line
+		prefix string
+
+		// indent is the indentation added to each nesting level, empty means compact output
+		indent string
+
+		// escapeHTML indicates whether to escape '<', '>' and '&' inside JSON strings
+		escapeHTML bool
+
+		// trailingNewline is the policy for the newline appended after the encoded body
+		trailingNewline TrailingNewline
+
+		// keyOrder is the policy for the order of the object keys
+		keyOrder KeyOrder
+	}
+)
+
+const (
+	// TrailingNewlineDefault keeps the behavior of each encoder, no newline for Encoder and a newline for
+	// StreamEncoder
+	TrailingNewlineDefault TrailingNewline = iota
+
+	// TrailingNewlineAlways always appends a newline after the encoded body
+	TrailingNewlineAlways
+
+	// TrailingNewlineNever never appends a newline after the encoded body
+	TrailingNewlineNever
+)
+
+const (
+	// KeyOrderDefault keeps the struct fields in declaration order and sorts the map keys
+	KeyOrderDefault KeyOrder = iota
+
+	// KeyOrderSorted sorts the keys of every object, including the ones from struct fields
+	KeyOrderSorted
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - prefix: the prefix added to each indented line
+//   - indent: the indentation added to each nesting level, empty means compact output
+//   - escapeHTML: indicates whether to escape '<', '>' and '&' inside JSON strings
+//   - trailingNewline: the policy for the newline appended after the encoded body
+//   - keyOrder: the policy for the order of the object keys
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	prefix string,
+	indent string,
+	escapeHTML bool,
+	trailingNewline TrailingNewline,
+	keyOrder KeyOrder,
+) *Options {
+	return &Options{
+		prefix:          prefix,
+		indent:          indent,
+		escapeHTML:      escapeHTML,
+		trailingNewline: trailingNewline,
+		keyOrder:        keyOrder,
+	}
+}
+
+// NewDefaultOptions creates a new Options instance that matches the encoding/json defaults
+//
+// Returns:
+//
+//   - *Options: the default Options instance
+func NewDefaultOptions() *Options {
+	return NewOptions(
+		"",
+		"",
+		true,
+		TrailingNewlineDefault,
+		KeyOrderDefault,
+	)
+}
+
+// hasTrailingNewline returns whether a newline must be appended after the encoded body
+//
+// Parameters:
+//
+//   - defaultTrailingNewline: the value used for the TrailingNewlineDefault policy
+//
+// Returns:
+//
+//   - bool: true if a newline must be appended
+func (o Options) hasTrailingNewline(defaultTrailingNewline bool) bool {
+	switch o.trailingNewline {
+	case TrailingNewlineAlways:
+		return true
+	case TrailingNewlineNever:
+		return false
+	default:
+		return defaultTrailingNewline
+	}
+}
+
+// newJSONEncoder creates a new encoding/json encoder configured with the options
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//
+// Returns:
+//
+//   - *json.Encoder: The configured encoder
+func (o Options) newJSONEncoder(writer io.Writer) *json.Encoder {
+	jsonEncoder := json.NewEncoder(writer)
+	jsonEncoder.SetEscapeHTML(o.escapeHTML)
+	jsonEncoder.SetIndent(o.prefix, o.indent)
+	return jsonEncoder
+}
+
+// appendEncoded encodes the body with the options and appends it to the buffer
+//
+// Parameters:
+//
+//   - buffer: The buffer to append the encoded body to
+//   - body: The body to encode
+//   - defaultTrailingNewline: the value used for the TrailingNewlineDefault policy
+//
+// Returns:
+//
+//   - error: The error if any
+func (o Options) appendEncoded(
+	buffer *bytes.Buffer,
+	body any,
+	defaultTrailingNewline bool,
+) error {
+	// Sort the keys of every object if required, the body is decoded into generic values whose map keys are
+	// sorted by encoding/json
+	if o.keyOrder == KeyOrderSorted {
+		sortedBody, err := o.toSortedValue(body)
+		if err != nil {
+			return err
+		}
+		body = sortedBody
+	}
+
+	// Encode the body, encoding/json always appends a newline
+	if err := o.newJSONEncoder(buffer).Encode(body); err != nil {
+		return err
+	}
+
+	// Remove the newline if it is not required
+	if !o.hasTrailingNewline(defaultTrailingNewline) {
+		buffer.Truncate(buffer.Len() - 1)
+	}
+	return nil
+}
+
+// encode encodes the body with the options into a pooled buffer and returns a copy of the encoded body
+//
+// Parameters:
+//
+//   - body: The body to encode
+//   - defaultTrailingNewline: the value used for the TrailingNewlineDefault policy
+//
+// Returns:
+//
+//   - []byte: The encoded JSON bytes
+//   - error: The error if any
+func (o Options) encode(
+	body any,
+	defaultTrailingNewline bool,
+) ([]byte, error) {
+	return o.appendTo(nil, body, defaultTrailingNewline)
+}
+
+// appendTo encodes the body with the options into a pooled buffer and appends it to the destination
+//
+// Parameters:
+//
+//   - dst: The destination to append the encoded body to
+//   - body: The body to encode
+//   - defaultTrailingNewline: the value used for the TrailingNewlineDefault policy
+//
+// Returns:
+//
+//   - []byte: The destination with the encoded JSON bytes appended
+//   - error: The error if any
+func (o Options) appendTo(
+	dst []byte,
+	body any,
+	defaultTrailingNewline bool,
+) ([]byte, error) {
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+
+	if err := o.appendEncoded(buffer, body, defaultTrailingNewline); err != nil {
+		return dst, err
+	}
+	return append(dst, buffer.Bytes()...), nil
+}
+
+// write encodes the body with the options into a pooled buffer and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the encoded body
+//   - body: The body to encode
+//   - defaultTrailingNewline: the value used for the TrailingNewlineDefault policy
+//
+// Returns:
+//
+//   - error: The error if any
+func (o Options) write(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+	defaultTrailingNewline bool,
+) error {
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+
+	// Encode the body before calling the before write function, so an encoding error can still be reported
+	if err := o.appendEncoded(buffer, body, defaultTrailingNewline); err != nil {
+		return err
+	}
+
+	// Call the before write function if provided
+	if beforeWriteFn != nil {
+		if fnErr := beforeWriteFn(); fnErr != nil {
+			return fnErr
+		}
+	}
+
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+// toSortedValue converts the body into generic JSON values, whose object keys are sorted when encoded
+//
+// Parameters:
+//
+//   - body: The body to convert
+//
+// Returns:
+//
+//   - any: The generic JSON value
+//   - error: The error if any
+func (o Options) toSortedValue(body any) (any, error) {
+	// Encode the body in compact form, keeping the HTML escaping setting
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+
+	jsonEncoder := json.NewEncoder(buffer)
+	jsonEncoder.SetEscapeHTML(o.escapeHTML)
+	if err := jsonEncoder.Encode(body); err != nil {
+		return nil, err
+	}
+
+	// Decode it into generic values, keeping the numbers as they were encoded
+	var value any
+	jsonDecoder := json.NewDecoder(buffer)
+	jsonDecoder.UseNumber()
+	if err := jsonDecoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
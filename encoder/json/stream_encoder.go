@@ -1,9 +1,7 @@
 package json
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
+	"context"
 	"io"
 
 	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
@@ -11,16 +9,29 @@ import (
 
 type (
 	// StreamEncoder is the JSON encoder struct
-	StreamEncoder struct{}
+	StreamEncoder struct {
+		options Options
+	}
 )
 
 // NewStreamEncoder creates a new JSON encoder
 //
+// Parameters:
+//
+//   - options: the additional settings for the encoder implementation, nil uses the default options
+//
 // Returns:
 //
-//   - *StreamEncoder: The default encoder
-func NewStreamEncoder() *StreamEncoder {
-	return &StreamEncoder{}
+//   - *StreamEncoder: The encoder
+func NewStreamEncoder(options *Options) *StreamEncoder {
+	// Initialize the options
+	if options == nil {
+		options = NewDefaultOptions()
+	}
+
+	return &StreamEncoder{
+		options: *options,
+	}
 }
 
 // Encode encodes the body into JSON
@@ -41,23 +52,12 @@ func (s StreamEncoder) Encode(
 		return nil, gojsonencoder.ErrNilBody
 	}
 
-	// Create a buffer to write to
-	buffer := new(bytes.Buffer)
-
-	// Wrap it with a bufio.Writer
-	writer := bufio.NewWriter(buffer)
-
-	// Encode the body into JSON
-	if err := json.NewEncoder(writer).Encode(body); err != nil {
-		return nil, err
-	}
-
-	// Flush to ensure all data is written to the underlying buffer
-	if err := writer.Flush(); err != nil {
+	// Encode the body into JSON, with trailing newline by default
+	jsonBody, err := s.options.encode(body, true)
+	if err != nil {
 		return nil, err
 	}
-
-	return buffer.Bytes(), nil
+	return jsonBody, nil
 }
 
 // EncodeAndWrite encodes the body into JSON and writes it to the writer
@@ -75,12 +75,39 @@ func (s StreamEncoder) EncodeAndWrite(
 	writer io.Writer,
 	beforeWriteFn func() error,
 	body any,
-) (err error) {
+) error {
+	return s.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body into JSON and writes it to the writer, nothing is written once the context
+// is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer
+//   - beforeWriteFn: The function to call before writing
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (s StreamEncoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
 	// Check if the writer is nil
 	if writer == nil {
 		return gojsonencoder.ErrNilWriter
 	}
 
+	// Check the context before writing
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+
 	// Call the before write function if provided
 	if beforeWriteFn != nil {
 		if fnErr := beforeWriteFn(); fnErr != nil {
@@ -88,6 +115,34 @@ func (s StreamEncoder) EncodeAndWrite(
 		}
 	}
 
-	// Encode the body into JSON
-	return json.NewEncoder(writer).Encode(body)
+	// Encode the body into JSON directly into the writer when the options allow it
+	if s.options.keyOrder == KeyOrderDefault && s.options.hasTrailingNewline(true) {
+		return s.options.newJSONEncoder(writer).Encode(body)
+	}
+
+	// Otherwise, encode the body into a pooled buffer first
+	return s.options.write(writer, nil, body, true)
+}
+
+// EncodeTo encodes the body and appends it to the destination, so the caller can reuse its buffer
+//
+// Parameters:
+//
+//   - dst: The destination to append the encoded body to
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - []byte: The destination with the encoded JSON bytes appended, or the unchanged destination on error
+//   - error: The error if any
+func (s StreamEncoder) EncodeTo(
+	dst []byte,
+	body any,
+) ([]byte, error) {
+	// Check if body is nil
+	if body == nil {
+		return dst, gojsonencoder.ErrNilBody
+	}
+
+	return s.options.appendTo(dst, body, true)
 }
@@ -0,0 +1,140 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type (
+	// Trailer writes the end of a streamed JSON array when the iteration fails after the opening bracket has been
+	// written, so the client can detect the failure
+	Trailer interface {
+		WriteTrailer(
+			writer io.Writer,
+			hasElements bool,
+			err error,
+		) error
+	}
+
+	// ErrorObjectTrailer appends a final error object to the array and closes it
+	ErrorObjectTrailer struct {
+		key       string
+		messageFn func(error) string
+	}
+
+	// HTTPTrailer sets the error message in an HTTP trailer and closes the array
+	HTTPTrailer struct {
+		header    http.Header
+		name      string
+		messageFn func(error) string
+	}
+)
+
+// errorMessage returns the message of the error sent to the client
+//
+// Parameters:
+//
+//   - messageFn: The function that maps the error to the message, nil uses the message of the error
+//   - err: The error that stopped the iteration
+//
+// Returns:
+//
+//   - string: The message of the error
+func errorMessage(messageFn func(error) string, err error) string {
+	if messageFn == nil {
+		return err.Error()
+	}
+	return messageFn(err)
+}
+
+// NewErrorObjectTrailer creates a new ErrorObjectTrailer instance. The message is written into the response body, so
+// the iteration errors that may carry internal details, such as the database errors, must be mapped to a message
+// that is safe to expose to the client
+//
+// Parameters:
+//
+//   - key: the key of the error message in the final error object
+//   - messageFn: maps the iteration error to the message written, nil writes the message of the error as is
+//
+// Returns:
+//
+//   - *ErrorObjectTrailer: the new ErrorObjectTrailer instance
+func NewErrorObjectTrailer(key string, messageFn func(error) string) *ErrorObjectTrailer {
+	return &ErrorObjectTrailer{
+		key:       key,
+		messageFn: messageFn,
+	}
+}
+
+// WriteTrailer writes the final error object and the closing bracket
+//
+// Parameters:
+//
+//   - writer: The writer to write the trailer to
+//   - hasElements: Whether elements were already written to the array
+//   - err: The error that stopped the iteration
+//
+// Returns:
+//
+//   - error: The error if any
+func (e ErrorObjectTrailer) WriteTrailer(
+	writer io.Writer,
+	hasElements bool,
+	err error,
+) error {
+	// Marshal the error object
+	errorObject, marshalErr := json.Marshal(map[string]string{e.key: errorMessage(e.messageFn, err)})
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	// Write the separator, the error object and the closing bracket
+	if hasElements {
+		errorObject = append([]byte{','}, errorObject...)
+	}
+	_, writeErr := writer.Write(append(errorObject, ']'))
+	return writeErr
+}
+
+// NewHTTPTrailer creates a new HTTPTrailer instance and declares the trailer in the response header, so it must be
+// created before the header is written. As with ErrorObjectTrailer, the message is sent to the client
+//
+// Parameters:
+//
+//   - header: the response header
+//   - name: the name of the trailer that carries the error message
+//   - messageFn: maps the iteration error to the message sent, nil sends the message of the error as is
+//
+// Returns:
+//
+//   - *HTTPTrailer: the new HTTPTrailer instance
+func NewHTTPTrailer(header http.Header, name string, messageFn func(error) string) *HTTPTrailer {
+	header.Add("Trailer", name)
+	return &HTTPTrailer{
+		header:    header,
+		name:      name,
+		messageFn: messageFn,
+	}
+}
+
+// WriteTrailer sets the error message in the HTTP trailer and writes the closing bracket
+//
+// Parameters:
+//
+//   - writer: The writer to write the closing bracket to
+//   - hasElements: Whether elements were already written to the array
+//   - err: The error that stopped the iteration
+//
+// Returns:
+//
+//   - error: The error if any
+func (h HTTPTrailer) WriteTrailer(
+	writer io.Writer,
+	hasElements bool,
+	err error,
+) error {
+	h.header.Set(h.name, errorMessage(h.messageFn, err))
+	_, writeErr := writer.Write([]byte{']'})
+	return writeErr
+}
@@ -0,0 +1,13 @@
+package jsonseq
+
+import (
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+const (
+	// ContentType is the media type of JSON text sequences
+	ContentType = gojsonsequence.JSONSeqContentType
+
+	// RecordSeparator is the ASCII RS character that precedes each JSON text
+	RecordSeparator = gojsonsequence.RecordSeparator
+)
@@ -0,0 +1,245 @@
+package jsonseq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+type (
+	// Encoder is the RFC 7464 JSON text sequences implementation of the Encoder interface, each record is encoded
+	// with the record encoder, preceded by the record separator and followed by a newline
+	Encoder struct {
+		recordEncoder gojsonencoder.Encoder
+	}
+
+	// Options are the additional settings for the encoder implementation
+	Options struct {
+		// recordEncoder is the encoder used to encode each record
+		recordEncoder gojsonencoder.Encoder
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - recordEncoder: the encoder used to encode each record, nil uses the plain JSON encoder. Use the protojson
+//     encoder for records with proto.Message fields
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	recordEncoder gojsonencoder.Encoder,
+) *Options {
+	return &Options{
+		recordEncoder: recordEncoder,
+	}
+}
+
+// NewEncoder creates a new JSON text sequences encoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the encoder implementation
+//
+// Returns:
+//
+//   - *Encoder: the new Encoder instance
+func NewEncoder(options *Options) *Encoder {
+	// Initialize the record encoder
+	var recordEncoder gojsonencoder.Encoder
+	if options != nil {
+		recordEncoder = options.recordEncoder
+	}
+	if recordEncoder == nil {
+		recordEncoder = gojsonencoderjson.NewEncoder(nil)
+	}
+
+	return &Encoder{
+		recordEncoder: recordEncoder,
+	}
+}
+
+// AppendRecord encodes a record and appends it to the buffer, preceded by the record separator and followed by a
+// newline
+//
+// Parameters:
+//
+//   - buffer: The buffer to append the record to
+//   - record: The record to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) AppendRecord(buffer *bytes.Buffer, record any) error {
+	// Encode the record
+	data, err := e.recordEncoder.Encode(record)
+	if err != nil {
+		return err
+	}
+
+	// Write the record separator, the compacted record and the newline
+	buffer.WriteByte(RecordSeparator)
+	if compactErr := json.Compact(buffer, data); compactErr != nil {
+		return compactErr
+	}
+	buffer.WriteByte('\n')
+	return nil
+}
+
+// Encode encodes the body into a JSON text sequence
+//
+// Parameters:
+//
+//   - body: The body to encode, each element of a slice or an array is encoded as one record, any other value is
+//     encoded as a single record
+//
+// Returns:
+//
+//   - []byte: The encoded JSON text sequence
+//   - error: The error if any
+func (e Encoder) Encode(
+	body any,
+) ([]byte, error) {
+	return e.encode(context.Background(), body)
+}
+
+// encode encodes the body into a JSON text sequence, checking the context before each record
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - body: The body to encode, each element of a slice or an array is encoded as one record, any other value is
+//     encoded as a single record
+//
+// Returns:
+//
+//   - []byte: The encoded JSON text sequence
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) encode(
+	ctx context.Context,
+	body any,
+) ([]byte, error) {
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	if err := e.appendRecords(ctx, buffer, body); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buffer.Bytes()), nil
+}
+
+// appendRecords encodes the records of the body and appends them to the buffer, checking the context before each
+// record
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - buffer: The buffer to append the records to
+//   - body: The body to encode, each element of a slice or an array is encoded as one record, any other value is
+//     encoded as a single record
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) appendRecords(
+	ctx context.Context,
+	buffer *bytes.Buffer,
+	body any,
+) error {
+	// Check if body is nil
+	if body == nil {
+		return gojsonencoder.ErrNilBody
+	}
+
+	// Encode each record
+	for i, record := range gojsonsequence.Records(body) {
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+		if err := e.AppendRecord(buffer, record); err != nil {
+			return fmt.Errorf(ErrEncodeRecord, i, err)
+		}
+	}
+	return nil
+}
+
+// EncodeAndWrite encodes the body into a JSON text sequence and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body into a JSON text sequence and writes it to the writer, nothing is written
+// once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	// Check if the writer is nil
+	if writer == nil {
+		return gojsonencoder.ErrNilWriter
+	}
+
+	// Encode the body into a pooled buffer, checking the context before each record
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	if err := e.appendRecords(ctx, buffer, body); err != nil {
+		return err
+	}
+
+	// Call the before write function if provided, once the context is checked
+	beforeWriteFn = gojsonencoder.ContextBeforeWriteFn(ctx, beforeWriteFn)
+	if beforeWriteFn != nil {
+		if fnErr := beforeWriteFn(); fnErr != nil {
+			return fnErr
+		}
+	}
+
+	// Write the JSON text sequence to the writer
+	_, writeErr := writer.Write(buffer.Bytes())
+	return writeErr
+}
+
+// ContentType returns the media type of the encoded body
+//
+// Returns:
+//
+//   - string: the media type
+func (e Encoder) ContentType() string {
+	return ContentType
+}
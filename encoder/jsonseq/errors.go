@@ -0,0 +1,5 @@
+package jsonseq
+
+const (
+	ErrEncodeRecord = "failed to encode record %d: %w"
+)
@@ -0,0 +1,392 @@
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+	gojsonbuffer "github.com/ralvarezdev/go-json/internal/buffer"
+	gojsonsequence "github.com/ralvarezdev/go-json/internal/sequence"
+)
+
+type (
+	// Encoder is the newline-delimited JSON (JSON Lines) encoder, each record is encoded with the record encoder
+	// and written as one line
+	Encoder struct {
+		recordEncoder gojsonencoder.Encoder
+		flushEvery    int
+	}
+
+	// Options are the additional settings for the encoder implementation
+	Options struct {
+		// recordEncoder is the encoder used to encode each record
+		recordEncoder gojsonencoder.Encoder
+
+		// flushEvery is the number of records written between flushes when the writer is an http.Flusher
+		flushEvery int
+	}
+)
+
+// NewOptions creates a new Options instance
+//
+// Parameters:
+//
+//   - recordEncoder: the encoder used to encode each record, nil uses the plain JSON encoder. Use the protojson
+//     encoder for records with proto.Message fields
+//   - flushEvery: the number of records written between flushes when the writer is an http.Flusher, 1 flushes after
+//     each line and 0 only flushes once all the records are written
+//
+// Returns:
+//
+//   - *Options: the new Options instance
+func NewOptions(
+	recordEncoder gojsonencoder.Encoder,
+	flushEvery int,
+) *Options {
+	return &Options{
+		recordEncoder: recordEncoder,
+		flushEvery:    flushEvery,
+	}
+}
+
+// NewEncoder creates a new NDJSON encoder
+//
+// Parameters:
+//
+//   - options: the additional settings for the encoder implementation
+//
+// Returns:
+//
+//   - *Encoder: the new Encoder instance
+func NewEncoder(options *Options) *Encoder {
+	// Initialize the settings
+	var recordEncoder gojsonencoder.Encoder
+	flushEvery := 0
+	if options != nil {
+		recordEncoder = options.recordEncoder
+		flushEvery = options.flushEvery
+	}
+
+	// Initialize the record encoder
+	if recordEncoder == nil {
+		recordEncoder = gojsonencoderjson.NewEncoder(nil)
+	}
+
+	return &Encoder{
+		recordEncoder: recordEncoder,
+		flushEvery:    flushEvery,
+	}
+}
+
+// appendRecord encodes a record and appends it as one line to the buffer
+//
+// Parameters:
+//
+//   - buffer: The buffer to append the line to
+//   - record: The record to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) appendRecord(buffer *bytes.Buffer, record any) error {
+	// Encode the record
+	data, err := e.recordEncoder.Encode(record)
+	if err != nil {
+		return err
+	}
+
+	// Remove the trailing newline added by some encoders
+	data = bytes.TrimRight(data, "\n")
+
+	// Compact the record if the encoder produced an indented output
+	if bytes.IndexByte(data, '\n') >= 0 {
+		if compactErr := json.Compact(buffer, data); compactErr != nil {
+			return compactErr
+		}
+	} else {
+		buffer.Write(data)
+	}
+	buffer.WriteByte('\n')
+	return nil
+}
+
+// Encode encodes the body into NDJSON bytes
+//
+// Parameters:
+//
+//   - body: The body to encode, each element of a slice or an array is encoded as one line, any other value is
+//     encoded as a single line
+//
+// Returns:
+//
+//   - []byte: The encoded NDJSON bytes
+//   - error: The error if any
+func (e Encoder) Encode(
+	body any,
+) ([]byte, error) {
+	// Check if body is nil
+	if body == nil {
+		return nil, gojsonencoder.ErrNilBody
+	}
+
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	for i, record := range gojsonsequence.Records(body) {
+		if err := e.appendRecord(buffer, record); err != nil {
+			return nil, fmt.Errorf(ErrEncodeRecord, i, err)
+		}
+	}
+	return bytes.Clone(buffer.Bytes()), nil
+}
+
+// EncodeAndWrite encodes the body into NDJSON and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode, each element of a slice or an array is encoded as one line, any other value is
+//     encoded as a single line
+//
+// Returns:
+//
+//   - error: The error if any
+func (e Encoder) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes the body into NDJSON and writes it to the writer, until the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode, each element of a slice or an array is encoded as one line, any other value is
+//     encoded as a single line
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	// Check if body is nil
+	if body == nil {
+		return gojsonencoder.ErrNilBody
+	}
+
+	return WriteSeqContext(ctx, &e, writer, beforeWriteFn, func(yield func(any) bool) {
+		for _, record := range gojsonsequence.Records(body) {
+			if !yield(record) {
+				return
+			}
+		}
+	})
+}
+
+// WriteSeq encodes each record of the sequence as one line and writes it to the writer, without buffering the
+// whole sequence
+//
+// Parameters:
+//
+//   - encoder: The NDJSON encoder, nil uses the default encoder
+//   - writer: The writer to write the records to
+//   - beforeWriteFn: The function to call once before writing the first byte, it is also called if the sequence
+//     is empty
+//   - seq: The sequence of records
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteSeq[T any](
+	encoder *Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq[T],
+) error {
+	return WriteSeqContext(context.Background(), encoder, writer, beforeWriteFn, seq)
+}
+
+// WriteSeqContext encodes each record of the sequence as one line and writes it to the writer, as WriteSeq does,
+// until the context is done. The context is checked before each record, the lines already written are flushed
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The NDJSON encoder, nil uses the default encoder
+//   - writer: The writer to write the records to
+//   - beforeWriteFn: The function to call once before writing the first byte, it is also called if the sequence
+//     is empty
+//   - seq: The sequence of records
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func WriteSeqContext[T any](
+	ctx context.Context,
+	encoder *Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	seq iter.Seq[T],
+) error {
+	// Check if the writer is nil
+	if writer == nil {
+		return gojsonencoder.ErrNilWriter
+	}
+
+	// Initialize the encoder
+	if encoder == nil {
+		encoder = NewEncoder(nil)
+	}
+
+	// Check if the writer can be flushed
+	flusher, isFlusher := writer.(http.Flusher)
+
+	// Encode and write each record
+	var err error
+	wroteFirst := false
+	index := 0
+	buffer := gojsonbuffer.Get(0)
+	defer gojsonbuffer.Put(buffer)
+	seq(func(record T) bool {
+		// Check the context
+		if err = context.Cause(ctx); err != nil {
+			return false
+		}
+
+		// Encode the record
+		buffer.Reset()
+		if err = encoder.appendRecord(buffer, record); err != nil {
+			err = fmt.Errorf(ErrEncodeRecord, index, err)
+			return false
+		}
+
+		// Call the before write function before the first byte
+		if !wroteFirst {
+			wroteFirst = true
+			if beforeWriteFn != nil {
+				if err = beforeWriteFn(); err != nil {
+					return false
+				}
+			}
+		}
+
+		// Write the line
+		if _, err = writer.Write(buffer.Bytes()); err != nil {
+			return false
+		}
+		index++
+
+		// Flush the batch if required
+		if isFlusher && encoder.flushEvery > 0 && index%encoder.flushEvery == 0 {
+			flusher.Flush()
+		}
+		return true
+	})
+	if err == nil {
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		// Flush the records written before the context was done
+		if wroteFirst && isFlusher {
+			flusher.Flush()
+		}
+		return err
+	}
+
+	// Call the before write function if the sequence was empty
+	if !wroteFirst && beforeWriteFn != nil {
+		if err = beforeWriteFn(); err != nil {
+			return err
+		}
+	}
+
+	// Flush the remaining records
+	if isFlusher {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// WriteChan encodes each record received from the channel as one line and writes it to the writer, until the
+// channel is closed
+//
+// Parameters:
+//
+//   - encoder: The NDJSON encoder, nil uses the default encoder
+//   - writer: The writer to write the records to
+//   - beforeWriteFn: The function to call once before writing the first byte, it is also called if the channel is
+//     closed without records
+//   - ch: The channel of records
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteChan[T any](
+	encoder *Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	ch <-chan T,
+) error {
+	return WriteChanContext(context.Background(), encoder, writer, beforeWriteFn, ch)
+}
+
+// WriteChanContext encodes each record received from the channel as one line and writes it to the writer, until the
+// channel is closed or the context is done. A receive blocked on the channel is also interrupted once the context is
+// done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The NDJSON encoder, nil uses the default encoder
+//   - writer: The writer to write the records to
+//   - beforeWriteFn: The function to call once before writing the first byte, it is also called if the channel is
+//     closed without records
+//   - ch: The channel of records
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func WriteChanContext[T any](
+	ctx context.Context,
+	encoder *Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	ch <-chan T,
+) error {
+	return WriteSeqContext(ctx, encoder, writer, beforeWriteFn, func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case record, ok := <-ch:
+				if !ok || !yield(record) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// ContentType returns the media type of the encoded body
+//
+// Returns:
+//
+//   - string: the media type
+func (e Encoder) ContentType() string {
+	return ContentType
+}
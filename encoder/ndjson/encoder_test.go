@@ -0,0 +1,167 @@
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// flushRecorder records the number of lines written at each flush
+type flushRecorder struct {
+	bytes.Buffer
+	flushedLines []int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushedLines = append(f.flushedLines, bytes.Count(f.Bytes(), []byte{'\n'}))
+}
+
+type record struct {
+	ID int `json:"id"`
+}
+
+func TestWriteSeq(t *testing.T) {
+	tests := []struct {
+		name        string
+		records     int
+		flushEvery  int
+		want        string
+		wantFlushes []int
+	}{
+		{
+			name:        "calls the before write function and flushes for the empty sequence",
+			flushEvery:  1,
+			wantFlushes: []int{0},
+		},
+		{
+			name:        "flushes after each line",
+			records:     3,
+			flushEvery:  1,
+			want:        "{\"id\":0}\n{\"id\":1}\n{\"id\":2}\n",
+			wantFlushes: []int{1, 2, 3, 3},
+		},
+		{
+			name:        "flushes every batch and the remaining lines",
+			records:     5,
+			flushEvery:  2,
+			want:        "{\"id\":0}\n{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n{\"id\":4}\n",
+			wantFlushes: []int{2, 4, 5},
+		},
+		{
+			name:        "flushes once all the lines are written",
+			records:     3,
+			want:        "{\"id\":0}\n{\"id\":1}\n{\"id\":2}\n",
+			wantFlushes: []int{3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				seq := func(yield func(record) bool) {
+					for i := range test.records {
+						if !yield(record{ID: i}) {
+							return
+						}
+					}
+				}
+				called := false
+				var writer flushRecorder
+				err := WriteSeq(
+					NewEncoder(NewOptions(nil, test.flushEvery)), &writer, func() error {
+						called = true
+						return nil
+					}, seq,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !called {
+					t.Error("before write function not called")
+				}
+				if writer.String() != test.want {
+					t.Errorf("written = %q, want %q", writer.String(), test.want)
+				}
+				if !slices.Equal(writer.flushedLines, test.wantFlushes) {
+					t.Errorf("flushed lines = %v, want %v", writer.flushedLines, test.wantFlushes)
+				}
+			},
+		)
+	}
+}
+
+// failingWriter fails every write with the error
+type failingWriter struct {
+	err error
+}
+
+func (f failingWriter) Write([]byte) (int, error) {
+	return 0, f.err
+}
+
+func TestWriteSeqStopsOnWriteError(t *testing.T) {
+	writeErr := errors.New("write: broken pipe")
+	pulled := 0
+	seq := func(yield func(record) bool) {
+		for i := range 3 {
+			pulled++
+			if !yield(record{ID: i}) {
+				return
+			}
+		}
+	}
+
+	// The client is gone, so no more records are pulled from the sequence
+	err := WriteSeq(nil, failingWriter{err: writeErr}, nil, seq)
+	if !errors.Is(err, writeErr) {
+		t.Fatalf("error = %v, want %v", err, writeErr)
+	}
+	if pulled != 1 {
+		t.Errorf("pulled records = %d, want 1", pulled)
+	}
+}
+
+func TestWriteSeqContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seq := func(yield func(record) bool) {
+		for i := 0; ; i++ {
+			if i == 2 {
+				cancel()
+			}
+			if !yield(record{ID: i}) {
+				return
+			}
+		}
+	}
+
+	// The records written before the cancellation are flushed, and the cause of the context is returned
+	var writer flushRecorder
+	err := WriteSeqContext(ctx, NewEncoder(NewOptions(nil, 0)), &writer, nil, seq)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+	if want := "{\"id\":0}\n{\"id\":1}\n"; writer.String() != want {
+		t.Errorf("written = %q, want %q", writer.String(), want)
+	}
+	if want := []int{2}; !slices.Equal(writer.flushedLines, want) {
+		t.Errorf("flushed lines = %v, want %v", writer.flushedLines, want)
+	}
+}
+
+func TestWriteChanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The receive blocked on the open channel is interrupted once the context is done
+	var writer flushRecorder
+	err := WriteChanContext(ctx, nil, &writer, nil, make(chan record))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+	if writer.Len() != 0 {
+		t.Errorf("written = %q, want nothing", writer.String())
+	}
+}
@@ -0,0 +1,5 @@
+package ndjson
+
+const (
+	ErrEncodeRecord = "failed to encode record %d: %w"
+)
@@ -0,0 +1,30 @@
+package protojson
+
+import (
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+// NewArrayEncoder creates a new JSON array encoder whose elements are precomputed by reflection with the given
+// encoder, so they can be written with gojsonencoderjson.WriteArraySeq, WriteArraySeq2 and WriteArrayChan
+//
+// Parameters:
+//
+//   - encoder: the encoder used to precompute and encode each element, nil uses the default encoder
+//   - trailer: writes the end of the array when the iteration fails midway, nil leaves the array unterminated
+//
+// Returns:
+//
+//   - *gojsonencoderjson.ArrayEncoder: the new ArrayEncoder instance
+func NewArrayEncoder(
+	encoder *Encoder,
+	trailer gojsonencoderjson.Trailer,
+) *gojsonencoderjson.ArrayEncoder {
+	// Initialize the encoder
+	if encoder == nil {
+		encoder = NewEncoder(nil)
+	}
+
+	return gojsonencoderjson.NewArrayEncoder(
+		gojsonencoderjson.NewArrayOptions(encoder, trailer),
+	)
+}
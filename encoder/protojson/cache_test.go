@@ -0,0 +1,23 @@
+package protojson
+
+import (
+	"testing"
+)
+
+func TestMapperCacheShared(t *testing.T) {
+	mapperCache := NewMapperCache(0)
+	first := NewEncoder(NewOptions(true, WithMapperCache(mapperCache)))
+	second := NewEncoder(NewOptions(true, WithMapperCache(mapperCache)))
+
+	// The mapper created by the first encoder is reused by the second one
+	body := benchmarkBody(t, 1)
+	if _, err := first.Encode(body); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Encode(body); err != nil {
+		t.Fatal(err)
+	}
+	if stats := mapperCache.Stats(); stats.Misses != 1 || stats.Hits != 1 || stats.Size != 1 {
+		t.Errorf("stats = %+v, want 1 miss, 1 hit and 1 entry", stats)
+	}
+}
@@ -1,12 +1,12 @@
 package protojson
 
 import (
+	"context"
 	"io"
+	"reflect"
 
 	"google.golang.org/protobuf/encoding/protojson"
 
-	goreflect "github.com/ralvarezdev/go-reflect"
-
 	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
 	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
 )
@@ -16,15 +16,21 @@ type (
 	Encoder struct {
 		jsonEncoder    *gojsonencoderjson.Encoder
 		marshalOptions protojson.MarshalOptions
-		cache          bool
-		cachedMappers  map[string]*Mapper
+		mapperCache    *MapperCache
+		mapper         *Mapper
 	}
 
 	// Options are the additional settings for the encoder implementation
 	Options struct {
 		// cache indicates whether to cache the precompute marshal by reflection functions
 		cache bool
+
+		// mapperCache is the cache of the mappers, it can be shared by several encoders
+		mapperCache *MapperCache
 	}
+
+	// Option sets an optional setting of the Options
+	Option func(options *Options)
 )
 
 // NewOptions creates a new Options instance
@@ -32,16 +38,38 @@ type (
 // Parameters:
 //
 // - cache: indicates whether to cache the precompute marshal by reflection functions
+// - opts: the optional settings, such as WithMapperCache
 //
 // Returns:
 //
 // - *Options: the new Options instance
 func NewOptions(
 	cache bool,
+	opts ...Option,
 ) *Options {
-	return &Options{
+	options := &Options{
 		cache: cache,
 	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithMapperCache sets the cache of the mappers used if the cache is enabled, it can be shared by several encoders.
+// Without it, an unbounded cache is created for the encoder
+//
+// Parameters:
+//
+//   - mapperCache: the cache of the mappers
+//
+// Returns:
+//
+//   - Option: the option
+func WithMapperCache(mapperCache *MapperCache) Option {
+	return func(options *Options) {
+		options.mapperCache = mapperCache
+	}
 }
 
 // NewEncoder creates a new Encoder instance
@@ -54,14 +82,17 @@ func NewOptions(
 //
 // - *Encoder: the new Encoder instance
 func NewEncoder(options *Options) *Encoder {
-	// Initialize cache setting
-	cache := false
-	if options != nil {
-		cache = options.cache
+	// Initialize the mapper cache, it is shared by the copies of the encoder
+	var mapperCache *MapperCache
+	if options != nil && options.cache {
+		mapperCache = options.mapperCache
+		if mapperCache == nil {
+			mapperCache = NewMapperCache(0)
+		}
 	}
 
 	// Initialize the JSON encoder
-	jsonEncoder := gojsonencoderjson.NewEncoder()
+	jsonEncoder := gojsonencoderjson.NewEncoder(nil)
 
 	// Initialize unmarshal options
 	marshalOptions := protojson.MarshalOptions{
@@ -71,10 +102,25 @@ func NewEncoder(options *Options) *Encoder {
 	return &Encoder{
 		jsonEncoder:    jsonEncoder,
 		marshalOptions: marshalOptions,
-		cache:          cache,
+		mapperCache:    mapperCache,
 	}
 }
 
+// WithMapper returns a copy of the encoder that precomputes the bodies of the mapper type with the given mapper,
+// without looking it up. The bodies of other types are still precomputed with their own mapper
+//
+// Parameters:
+//
+// - mapper: the mapper built for the type of the encoded bodies
+//
+// Returns:
+//
+// - *Encoder: the copy of the encoder
+func (e Encoder) WithMapper(mapper *Mapper) *Encoder {
+	e.mapper = mapper
+	return &e
+}
+
 // PrecomputeMarshal precomputes the marshaled body by reflecting on the instance
 //
 // Parameters:
@@ -86,54 +132,59 @@ func NewEncoder(options *Options) *Encoder {
 // - (map[string]any, error): The precomputed marshaled body and the error if any
 func (e Encoder) PrecomputeMarshal(
 	body any,
+) (map[string]any, error) {
+	return e.PrecomputeMarshalContext(context.Background(), body)
+}
+
+// PrecomputeMarshalContext precomputes the marshaled body by reflecting on the instance, stopping once the context
+// is done
+//
+// Parameters:
+//
+// - ctx: The context
+// - body: The body to precompute the marshaled body for
+//
+// Returns:
+//
+// - (map[string]any, error): The precomputed marshaled body and the error if any, the cause of the context if it is
+// done
+func (e Encoder) PrecomputeMarshalContext(
+	ctx context.Context,
+	body any,
 ) (map[string]any, error) {
 	// Check if body is nil
 	if body == nil {
 		return nil, gojsonencoder.ErrNilBody
 	}
 
-	// Check if the cache is true, if so try to get the mapper from the cache
-	if e.cache && e.cachedMappers != nil {
-		// Get the unique type identifier for the body
-		uniqueTypeReference := goreflect.UniqueTypeReference(body)
-
-		// Check if the mapper exists in the cache
-		if mapper, ok := e.cachedMappers[uniqueTypeReference]; ok {
-			precomputedMarshal, err := mapper.PrecomputeMarshalByReflection(body, &e.marshalOptions)
-			if err != nil {
-				return nil, err
-			}
-			return precomputedMarshal, nil
-		}
-	}
-
-	// Create the cache map if it doesn't exist
-	if e.cache && e.cachedMappers == nil {
-		e.cachedMappers = make(map[string]*Mapper)
-	}
-
-	// Create a new mapper and store it in the cache if caching is enabled
-	mapper, err := NewMapper(body)
+	// Get the mapper of the body type
+	mapper, err := e.lookupMapper(body)
 	if err != nil {
 		return nil, err
 	}
-	if e.cache {
-		// Get the unique type identifier for the body
-		uniqueTypeReference := goreflect.UniqueTypeReference(body)
-
-		// Store the mapper in the cache
-		e.cachedMappers[uniqueTypeReference] = mapper
-	}
 
 	// Marshal the instance to get the precomputed body
-	precomputedMarshal, err := mapper.PrecomputeMarshalByReflection(
-		body,
-		&e.marshalOptions,
-	)
-	if err != nil {
-		return nil, err
+	return mapper.precomputeMarshal(ctx, body, &e.marshalOptions)
+}
+
+// lookupMapper returns the mapper of the body type, the mapper of the encoder if the body is of its type, or the
+// cached one if the cache is enabled
+//
+// Parameters:
+//
+// - body: The body to get the mapper for
+//
+// Returns:
+//
+// - (*Mapper, error): The mapper and the error if any
+func (e Encoder) lookupMapper(body any) (*Mapper, error) {
+	if e.mapper.matches(body) {
+		return e.mapper, nil
+	}
+	if e.mapperCache != nil {
+		return e.mapperCache.Mapper(reflect.TypeOf(body))
 	}
-	return precomputedMarshal, nil
+	return NewMapper(body)
 }
 
 // Encode encodes the given body to JSON
@@ -176,20 +227,70 @@ func (e Encoder) EncodeAndWrite(
 	writer io.Writer,
 	beforeWriteFn func() error,
 	body any,
+) error {
+	return e.EncodeAndWriteContext(context.Background(), writer, beforeWriteFn, body)
+}
+
+// EncodeAndWriteContext encodes and writes the given body to the writer, nothing is written once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+// - error: The error if any, the cause of the context if it is done
+func (e Encoder) EncodeAndWriteContext(
+	ctx context.Context,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
 ) error {
 	// Check if the writer is nil
 	if writer == nil {
 		return gojsonencoder.ErrNilWriter
 	}
 
-	// Marshal the instance to get the precomputed body
-	precomputedMarshal, err := e.PrecomputeMarshal(body)
+	// Marshal the instance to get the precomputed body, checking the context before each field
+	precomputedMarshal, err := e.PrecomputeMarshalContext(ctx, body)
 	if err != nil {
 		return err
 	}
-	return e.jsonEncoder.EncodeAndWrite(
+	return e.jsonEncoder.EncodeAndWriteContext(
+		ctx,
 		writer,
 		beforeWriteFn,
 		precomputedMarshal,
 	)
 }
+
+// EncodeTo encodes the given body to JSON and appends it to the destination, so the caller can reuse its buffer
+//
+// Parameters:
+//
+//   - dst: The destination to append the encoded body to
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - ([]byte, error): The destination with the encoded body appended, or the unchanged destination, and the error
+//     if any
+func (e Encoder) EncodeTo(
+	dst []byte,
+	body any,
+) ([]byte, error) {
+	// Check if body is nil
+	if body == nil {
+		return dst, gojsonencoder.ErrNilBody
+	}
+
+	// Marshal the instance to get the precomputed body
+	precomputedMarshal, err := e.PrecomputeMarshal(body)
+	if err != nil {
+		return dst, err
+	}
+	return e.jsonEncoder.EncodeTo(dst, precomputedMarshal)
+}
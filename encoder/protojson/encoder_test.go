@@ -0,0 +1,228 @@
+package protojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type benchmarkItem struct {
+	ID        int                    `json:"id"`
+	Name      string                 `json:"name"`
+	CreatedAt *timestamppb.Timestamp `json:"created_at"`
+	Metadata  *structpb.Struct       `json:"metadata"`
+}
+
+type benchmarkPage struct {
+	Items []benchmarkItem `json:"items"`
+	Total int             `json:"total"`
+}
+
+// benchmarkBody returns a body of the given number of items
+func benchmarkBody(tb testing.TB, items int) *benchmarkPage {
+	metadata, err := structpb.NewStruct(map[string]any{"source": "benchmark", "retries": 3})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	body := make([]benchmarkItem, items)
+	for i := range body {
+		body[i] = benchmarkItem{
+			ID:        i,
+			Name:      "item",
+			CreatedAt: timestamppb.Now(),
+			Metadata:  metadata,
+		}
+	}
+	return &benchmarkPage{Items: body, Total: items}
+}
+
+func BenchmarkEncoderEncode(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		options *Options
+		items   int
+	}{
+		{name: "small", items: 1},
+		{name: "small cached", options: NewOptions(true), items: 1},
+		{name: "large", items: 1000},
+		{name: "large cached", options: NewOptions(true), items: 1000},
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(
+			benchmark.name, func(b *testing.B) {
+				encoder := NewEncoder(benchmark.options)
+				body := benchmarkBody(b, benchmark.items)
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := encoder.Encode(body); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkEncoderEncodeAndWrite(b *testing.B) {
+	encoder := NewEncoder(NewOptions(true))
+	body := benchmarkBody(b, 1000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := encoder.EncodeAndWrite(io.Discard, nil, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type nestedMessage struct {
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+type nestedBody struct {
+	Time          time.Time      `json:"time"`
+	TimePointer   *time.Time     `json:"time_pointer"`
+	Nested        nestedMessage  `json:"nested"`
+	NestedPointer *nestedMessage `json:"nested_pointer"`
+	Point         image.Point    `json:"point"`
+	PointPointer  *image.Point   `json:"point_pointer"`
+}
+
+func TestEncoderEncodeNestedStructs(t *testing.T) {
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	file := &descriptorpb.FileDescriptorProto{Name: proto.String("a.proto"), Syntax: proto.String("proto3")}
+
+	tests := []struct {
+		name string
+		body *nestedBody
+		want string
+	}{
+		{
+			name: "encodes the structs without proto.Message fields as encoding/json does",
+			body: &nestedBody{
+				Time:         createdAt,
+				TimePointer:  &createdAt,
+				Point:        image.Point{X: 1, Y: 2},
+				PointPointer: &image.Point{X: 3, Y: 4},
+			},
+			want: `{"nested":{"file":null},"nested_pointer":null,` +
+				`"point":{"X":1,"Y":2},"point_pointer":{"X":3,"Y":4},` +
+				`"time":"2026-10-17T12:00:00Z","time_pointer":"2026-10-17T12:00:00Z"}`,
+		},
+		{
+			name: "encodes the proto.Message fields of the value and pointer structs",
+			body: &nestedBody{Time: createdAt, Nested: nestedMessage{File: file}, NestedPointer: &nestedMessage{File: file}},
+			want: `{"nested":{"file":{"name":"a.proto","syntax":"proto3"}},` +
+				`"nested_pointer":{"file":{"name":"a.proto","syntax":"proto3"}},` +
+				`"point":{"X":0,"Y":0},"point_pointer":null,` +
+				`"time":"2026-10-17T12:00:00Z","time_pointer":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := NewEncoder(nil).Encode(test.body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Compare the compacted documents, protojson randomizes its whitespace and the members are sorted
+				var compacted bytes.Buffer
+				if err = json.Compact(&compacted, got); err != nil {
+					t.Fatal(err)
+				}
+				if compacted.String() != test.want {
+					t.Errorf("encoded = %s, want %s", compacted.String(), test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestEncoderEncodeCollections(t *testing.T) {
+	a := &descriptorpb.FileDescriptorProto{Name: proto.String("a.proto")}
+	b := &descriptorpb.FileDescriptorProto{Name: proto.String("b.proto")}
+
+	tests := []struct {
+		name string
+		body any
+		want string
+	}{
+		{
+			name: "encodes the slices of proto.Message",
+			body: &struct {
+				Files []*descriptorpb.FileDescriptorProto `json:"files"`
+			}{Files: []*descriptorpb.FileDescriptorProto{a, b}},
+			want: `{"files":[{"name":"a.proto"},{"name":"b.proto"}]}`,
+		},
+		{
+			name: "encodes the maps of proto.Message",
+			body: &struct {
+				Files map[string]*descriptorpb.FileDescriptorProto `json:"files"`
+			}{Files: map[string]*descriptorpb.FileDescriptorProto{"b": b, "a": a}},
+			want: `{"files":{"a":{"name":"a.proto"},"b":{"name":"b.proto"}}}`,
+		},
+		{
+			name: "encodes the nested slices of proto.Message",
+			body: &struct {
+				Files [][]*descriptorpb.FileDescriptorProto `json:"files"`
+			}{Files: [][]*descriptorpb.FileDescriptorProto{{a}, {}, {a, b}}},
+			want: `{"files":[[{"name":"a.proto"}],[],[{"name":"a.proto"},{"name":"b.proto"}]]}`,
+		},
+		{
+			name: "encodes the maps of slices of proto.Message",
+			body: &struct {
+				Files map[string][]*descriptorpb.FileDescriptorProto `json:"files"`
+			}{Files: map[string][]*descriptorpb.FileDescriptorProto{"all": {a, b}, "none": nil}},
+			want: `{"files":{"all":[{"name":"a.proto"},{"name":"b.proto"}],"none":null}}`,
+		},
+		{
+			name: "encodes the nil elements and collections as null",
+			body: &struct {
+				Files       []*descriptorpb.FileDescriptorProto          `json:"files"`
+				FilesByName map[string]*descriptorpb.FileDescriptorProto `json:"files_by_name"`
+				NilFiles    []*descriptorpb.FileDescriptorProto          `json:"nil_files"`
+			}{
+				Files:       []*descriptorpb.FileDescriptorProto{nil, a},
+				FilesByName: map[string]*descriptorpb.FileDescriptorProto{"a": nil},
+			},
+			want: `{"files":[null,{"name":"a.proto"}],"files_by_name":{"a":null},"nil_files":null}`,
+		},
+		{
+			name: "encodes the maps with non-string keys as encoding/json does",
+			body: &struct {
+				Files map[int]*descriptorpb.FileDescriptorProto `json:"files"`
+			}{Files: map[int]*descriptorpb.FileDescriptorProto{10: b, 2: a}},
+			want: `{"files":{"10":{"name":"b.proto"},"2":{"name":"a.proto"}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := NewEncoder(nil).Encode(test.body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Compare the compacted documents, protojson randomizes its whitespace
+				var compacted bytes.Buffer
+				if err = json.Compact(&compacted, got); err != nil {
+					t.Fatal(err)
+				}
+				if compacted.String() != test.want {
+					t.Errorf("encoded = %s, want %s", compacted.String(), test.want)
+				}
+			},
+		)
+	}
+}
@@ -7,10 +7,16 @@ import (
 const (
 	ErrFieldNotHandled      = "field not handled on encoding: %s"
 	ErrFieldNotProtoMessage = "field is not a proto message: %s"
+	ErrValueNotProtoMessage = "value of type %s is not a proto message"
+	ErrMarshalField         = "failed to marshal field %s: %w"
+	ErrMarshalIndex         = "index %d: %w"
+	ErrMarshalKey           = "key %v: %w"
+	ErrTypeNotStruct        = "type %s is not a struct or a pointer to a struct"
 )
 
 var (
 	ErrNilBody           = errors.New("body is nil")
 	ErrNilMapper         = errors.New("encoder mapper is nil")
 	ErrNilStructInstance = errors.New("struct instance is nil")
+	ErrNilType           = errors.New("type is nil")
 )
@@ -1,6 +1,7 @@
 package protojson
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -14,38 +15,100 @@ import (
 type (
 	// Mapper is the protoJSON mapper struct
 	Mapper struct {
-		reflectType        reflect.Type
-		optionalFields     map[string]struct{}
-		protoMessageFields map[string]struct{}
-		regularFields      map[string]struct{}
-		jsonFieldNames     map[string]string
-		nestedStructs      map[string]*Mapper
+		reflectType           reflect.Type
+		optionalFields        map[string]struct{}
+		protoMessageFields    map[string]struct{}
+		protoCollectionFields map[string]struct{}
+		regularFields         map[string]struct{}
+		jsonFieldNames        map[string]string
+		nestedStructs         map[string]*Mapper
 	}
 )
 
+var (
+	// protoMessageType is the reflect type of the proto.Message interface
+	protoMessageType = reflect.TypeFor[proto.Message]()
+
+	// anyType is the reflect type of the empty interface
+	anyType = reflect.TypeFor[any]()
+)
+
 // NewMapper creates a new protoJSON mapper
 //
 // Parameters:
 //
-//   - structInstance: instance of the struct to create the mapper from
+//   - structInstance: instance of the struct to create the mapper from, it can be a nil pointer to the struct
 //
 // Returns:
 //
 // - *Mapper: instance of the mapper
-// - error: error if the struct instance is nil
+// - error: error if the struct instance is nil or is not a struct
 func NewMapper(structInstance any) (*Mapper, error) {
 	// Check if the struct instance is nil
 	if structInstance == nil {
 		return nil, ErrNilStructInstance
 	}
+	return NewMapperForType(reflect.TypeOf(structInstance))
+}
 
-	// Reflection of data
-	reflectedType := goreflect.GetDereferencedType(structInstance)
-	reflectedValue := goreflect.GetDereferencedValue(structInstance)
+// NewMapperForType creates a new protoJSON mapper from the struct type. The fields are classified from their static
+// types, so the mapper is valid for any value of the type and can be built at init time
+//
+// Parameters:
+//
+//   - reflectType: the struct type, or the pointer to the struct type, to create the mapper from
+//
+// Returns:
+//
+// - *Mapper: instance of the mapper
+// - error: error if the type is nil or is not a struct
+func NewMapperForType(reflectType reflect.Type) (*Mapper, error) {
+	// Check if the type is nil
+	if reflectType == nil {
+		return nil, ErrNilType
+	}
+
+	// Dereference the pointer type and check if it is a struct
+	if reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	if reflectType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(ErrTypeNotStruct, reflectType)
+	}
+	return newMapper(reflectType, make(map[reflect.Type]*Mapper))
+}
+
+// NewMapperFor creates a new protoJSON mapper from the type parameter, as NewMapperForType does
+//
+// Returns:
+//
+// - *Mapper: instance of the mapper
+// - error: error if the type parameter is not a struct or a pointer to a struct
+func NewMapperFor[T any]() (*Mapper, error) {
+	return NewMapperForType(reflect.TypeFor[T]())
+}
+
+// newMapper creates a new protoJSON mapper from the struct type, reusing the mappers of the struct types that are
+// being built so the recursive types are mapped once
+//
+// Parameters:
+//
+//   - reflectedType: the struct type to create the mapper from
+//   - building: the mappers of the struct types that are being built, by type
+//
+// Returns:
+//
+// - *Mapper: instance of the mapper
+// - error: error if any
+func newMapper(reflectedType reflect.Type, building map[reflect.Type]*Mapper) (*Mapper, error) {
+	// Register the mapper before mapping the fields, so the pointers to the same type reuse it
+	mapper := &Mapper{}
+	building[reflectedType] = mapper
 
 	// Prepare the different maps
 	optionalFields := make(map[string]struct{})
 	protoMessageFields := make(map[string]struct{})
+	protoCollectionFields := make(map[string]struct{})
 	regularFields := make(map[string]struct{})
 	jsonFieldNames := make(map[string]string)
 	nestedStructs := make(map[string]*Mapper)
@@ -54,12 +117,11 @@ func NewMapper(structInstance any) (*Mapper, error) {
 	for i := 0; i < reflectedType.NumField(); i++ {
 		// Get the field type through reflection
 		structField := reflectedType.Field(i)
-		fieldValue := reflectedValue.Field(i)
 		fieldType := structField.Type
 		fieldName := structField.Name
 
 		// Check if the field can be interfaced
-		if !fieldValue.CanInterface() {
+		if !structField.IsExported() {
 			continue
 		}
 
@@ -83,37 +145,156 @@ func NewMapper(structInstance any) (*Mapper, error) {
 		// Store the JSON field name
 		jsonFieldNames[fieldName] = jsonFieldName
 
-		// Get the field interface value
-		fieldInterfaceValue := fieldValue.Interface()
-
 		// Handle proto.Message fields
-		switch fieldInterfaceValue.(type) {
-		case proto.Message:
+		if fieldType.Implements(protoMessageType) {
 			// Set the field as a protoMessageField
 			protoMessageFields[fieldName] = struct{}{}
-		default:
-			if fieldType.Kind() != reflect.Struct {
-				// Store as regular field
-				regularFields[fieldName] = struct{}{}
-				break
-			}
+			continue
+		}
 
-			// Recursively handle nested structs
-			nestedMapper, mapperErr := NewMapper(fieldInterfaceValue)
-			if mapperErr != nil {
-				return nil, mapperErr
-			}
-			nestedStructs[fieldName] = nestedMapper
+		if isProtoMessageCollection(fieldType) {
+			// Store as a collection of proto.Message, its elements are marshaled one by one
+			protoCollectionFields[fieldName] = struct{}{}
+			continue
+		}
+
+		// Recursively handle nested structs and pointers to structs
+		nestedMapper, mapperErr := newNestedMapper(fieldType, building)
+		if mapperErr != nil {
+			return nil, mapperErr
+		}
+		if nestedMapper == nil {
+			// Store as regular field
+			regularFields[fieldName] = struct{}{}
+			continue
+		}
+		nestedStructs[fieldName] = nestedMapper
+	}
+	*mapper = Mapper{
+		reflectType:           reflectedType,
+		optionalFields:        optionalFields,
+		protoMessageFields:    protoMessageFields,
+		protoCollectionFields: protoCollectionFields,
+		regularFields:         regularFields,
+		jsonFieldNames:        jsonFieldNames,
+		nestedStructs:         nestedStructs,
+	}
+	return mapper, nil
+}
+
+// newNestedMapper returns the mapper of a struct field, or of a pointer to struct field at any depth
+//
+// Parameters:
+//
+//   - fieldType: the type of the field
+//   - building: the mappers of the struct types that are being built, by type
+//
+// Returns:
+//
+//   - *Mapper: the mapper of the struct type, nil if the field is not a struct, is a proto.Message struct, or is a
+//     struct or a pointer to a struct without proto.Message fields, so it is left to encoding/json. This keeps the
+//     encoding/json behavior of the types with their own marshaling, such as time.Time, and the value and pointer
+//     fields are encoded the same way
+//   - error: error if the mapper of the struct type cannot be created
+func newNestedMapper(fieldType reflect.Type, building map[reflect.Type]*Mapper) (*Mapper, error) {
+	// Dereference the pointer type at any depth
+	structType := fieldType
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct || reflect.PointerTo(structType).Implements(protoMessageType) {
+		return nil, nil
+	}
+
+	// Leave the structs without proto.Message fields to encoding/json, they are checked from their type alone so
+	// their fields do not need JSON tags
+	if !hasProtoMessageFields(structType, make(map[reflect.Type]struct{})) {
+		return nil, nil
+	}
+
+	// Reuse the mapper of the struct type if it is already being built, it is a recursive type
+	if nestedMapper, ok := building[structType]; ok {
+		return nestedMapper, nil
+	}
+	return newMapper(structType, building)
+}
+
+// hasProtoMessageFields returns whether the struct type has proto.Message fields, directly, in its collections or in
+// its nested structs, as the mapper classifies them
+//
+// Parameters:
+//
+//   - structType: the struct type to check
+//   - visited: the struct types already checked, the recursive types are checked once
+//
+// Returns:
+//
+//   - bool: whether the struct type has proto.Message fields
+func hasProtoMessageFields(structType reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[structType]; ok {
+		return false
+	}
+	visited[structType] = struct{}{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		// Check the proto.Message fields and the collections of them
+		fieldType := structField.Type
+		if fieldType.Implements(protoMessageType) || isProtoMessageCollection(fieldType) {
+			return true
+		}
+
+		// Check the nested structs and pointers to structs at any depth
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct &&
+			!reflect.PointerTo(fieldType).Implements(protoMessageType) &&
+			hasProtoMessageFields(fieldType, visited) {
+			return true
 		}
 	}
-	return &Mapper{
-		reflectType:        reflectedType,
-		optionalFields:     optionalFields,
-		protoMessageFields: protoMessageFields,
-		regularFields:      regularFields,
-		jsonFieldNames:     jsonFieldNames,
-		nestedStructs:      nestedStructs,
-	}, nil
+	return false
+}
+
+// isProtoMessageCollection returns whether the type is a slice, an array or a map whose elements are proto.Message,
+// at any depth of nested slices, arrays and maps
+//
+// Parameters:
+//
+//   - reflectType: The type to check
+//
+// Returns:
+//
+//   - bool: whether the type is a collection of proto.Message
+func isProtoMessageCollection(reflectType reflect.Type) bool {
+	switch reflectType.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		elemType := reflectType.Elem()
+		if elemType.Implements(protoMessageType) {
+			return true
+		}
+		return isProtoMessageCollection(elemType)
+	default:
+		return false
+	}
+}
+
+// matches returns whether the mapper was built for the type of the value
+//
+// Parameters:
+//
+//   - value: The value, or the pointer to the value
+//
+// Returns:
+//
+//   - bool: whether the mapper is not nil and was built for the type of the value
+func (m *Mapper) matches(value any) bool {
+	return m != nil && goreflect.GetDereferencedType(value) == m.reflectType
 }
 
 // PrecomputeMarshalByReflection marshals a struct to a map[string]any using reflection, handling nested
@@ -131,6 +312,26 @@ func NewMapper(structInstance any) (*Mapper, error) {
 func (m *Mapper) PrecomputeMarshalByReflection(
 	body any,
 	marshalOptions *protojson.MarshalOptions,
+) (map[string]any, error) {
+	return m.precomputeMarshal(context.Background(), body, marshalOptions)
+}
+
+// precomputeMarshal marshals a struct to a map[string]any using reflection, checking the context before each field
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - body: The struct to marshal
+//   - marshalOptions: The protojson.MarshalOptions to use (optional)
+//
+// Returns:
+//
+//   - map[string]any: The marshaled struct as a map
+//   - error: The error if any, the cause of the context if it is done
+func (m *Mapper) precomputeMarshal(
+	ctx context.Context,
+	body any,
+	marshalOptions *protojson.MarshalOptions,
 ) (map[string]any, error) {
 	// Check if the mapper is nil
 	if m == nil {
@@ -155,6 +356,11 @@ func (m *Mapper) PrecomputeMarshalByReflection(
 
 	// Handle nested proto.Message fields
 	for i := 0; i < reflectValue.NumField(); i++ {
+		// Check the context
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+
 		// Get the field type through reflection
 		structField := m.reflectType.Field(i)
 		fieldValue := reflectValue.Field(i)
@@ -184,9 +390,20 @@ func (m *Mapper) PrecomputeMarshalByReflection(
 
 		// Check if the field is a nested struct
 		if nestedMapper, nestedOk := m.nestedStructs[fieldName]; nestedOk {
+			// Dereference the pointers at any depth, the nil pointers are encoded as null
+			nestedValue := fieldValue
+			for nestedValue.Kind() == reflect.Ptr && !nestedValue.IsNil() {
+				nestedValue = nestedValue.Elem()
+			}
+			if nestedValue.Kind() == reflect.Ptr {
+				result[jsonFieldName] = nil
+				continue
+			}
+
 			// Recursively process the nested struct
-			nestedResult, err := nestedMapper.PrecomputeMarshalByReflection(
-				fieldValueInterface,
+			nestedResult, err := nestedMapper.precomputeMarshal(
+				ctx,
+				nestedValue.Interface(),
 				marshalOptions,
 			)
 			if err != nil {
@@ -196,8 +413,25 @@ func (m *Mapper) PrecomputeMarshalByReflection(
 			continue
 		}
 
+		// Check if the field is a collection of proto.Message
+		if _, protoCollectionOk := m.protoCollectionFields[fieldName]; protoCollectionOk {
+			// Marshal each element, the collection is kept as generic values so encoding/json writes it
+			collection, err := precomputeCollection(ctx, fieldValue, marshalOptions)
+			if err != nil {
+				return nil, fmt.Errorf(ErrMarshalField, fieldName, err)
+			}
+			result[jsonFieldName] = collection
+			continue
+		}
+
 		// Check if the field is a proto.Message field
 		if _, protoMessageOk := m.protoMessageFields[fieldName]; protoMessageOk {
+			// The nil proto.Message are encoded as null, as encoding/json does for nil pointers
+			if (fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface) && fieldValue.IsNil() {
+				result[jsonFieldName] = nil
+				continue
+			}
+
 			// Get the field value as proto.Message
 			protoMessage, protoOk := fieldValueInterface.(proto.Message)
 			if !protoOk {
@@ -220,3 +454,82 @@ func (m *Mapper) PrecomputeMarshalByReflection(
 	}
 	return result, nil
 }
+
+// precomputeCollection marshals each proto.Message of a slice, an array or a map, and returns the collection as
+// generic values. The nil slices, maps and messages are returned as nil so they are encoded as null, as
+// encoding/json does. The map keys keep their type, so encoding/json converts them as it does for any map
+//
+// Parameters:
+//
+//   - ctx: The context, it is checked before each element
+//   - value: The collection or the proto.Message element
+//   - marshalOptions: The protojson.MarshalOptions to use
+//
+// Returns:
+//
+//   - any: The []any, the map with any values, or the json.RawMessage of the element
+//   - error: The error if any, the cause of the context if it is done
+func precomputeCollection(
+	ctx context.Context,
+	value reflect.Value,
+	marshalOptions *protojson.MarshalOptions,
+) (any, error) {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil, nil
+		}
+
+		// Marshal each element
+		result := make([]any, value.Len())
+		for i := range value.Len() {
+			if err := context.Cause(ctx); err != nil {
+				return nil, err
+			}
+			element, err := precomputeCollection(ctx, value.Index(i), marshalOptions)
+			if err != nil {
+				return nil, fmt.Errorf(ErrMarshalIndex, i, err)
+			}
+			result[i] = element
+		}
+		return result, nil
+	case reflect.Map:
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		// Marshal each value, keeping the key type
+		result := reflect.MakeMapWithSize(reflect.MapOf(value.Type().Key(), anyType), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := context.Cause(ctx); err != nil {
+				return nil, err
+			}
+			element, err := precomputeCollection(ctx, iter.Value(), marshalOptions)
+			if err != nil {
+				return nil, fmt.Errorf(ErrMarshalKey, iter.Key().Interface(), err)
+			}
+			if element == nil {
+				result.SetMapIndex(iter.Key(), reflect.Zero(anyType))
+			} else {
+				result.SetMapIndex(iter.Key(), reflect.ValueOf(element))
+			}
+		}
+		return result.Interface(), nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+	}
+
+	// Marshal the element
+	protoMessage, ok := value.Interface().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf(ErrValueNotProtoMessage, value.Type())
+	}
+	data, err := marshalOptions.Marshal(protoMessage)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
@@ -0,0 +1,81 @@
+package protojson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type mapperBody struct {
+	Name string                            `json:"name"`
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+func TestNewMapperForType(t *testing.T) {
+	tests := []struct {
+		name        string
+		reflectType reflect.Type
+		wantErr     error
+		wantErrMsg  string
+	}{
+		{
+			name:        "builds the mappers of the struct types",
+			reflectType: reflect.TypeFor[mapperBody](),
+		},
+		{
+			name:        "builds the mappers of the pointers to struct types",
+			reflectType: reflect.TypeFor[*mapperBody](),
+		},
+		{
+			name:    "rejects the nil types",
+			wantErr: ErrNilType,
+		},
+		{
+			name:        "rejects the types that are not structs",
+			reflectType: reflect.TypeFor[string](),
+			wantErrMsg:  fmt.Sprintf(ErrTypeNotStruct, reflect.TypeFor[string]()),
+		},
+		{
+			name:        "rejects the interface types",
+			reflectType: reflect.TypeFor[any](),
+			wantErrMsg:  fmt.Sprintf(ErrTypeNotStruct, reflect.TypeFor[any]()),
+		},
+		{
+			name:        "rejects the pointers to pointers to struct types",
+			reflectType: reflect.TypeFor[**mapperBody](),
+			wantErrMsg:  fmt.Sprintf(ErrTypeNotStruct, reflect.TypeFor[*mapperBody]()),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				mapper, err := NewMapperForType(test.reflectType)
+				switch {
+				case test.wantErr != nil:
+					if !errors.Is(err, test.wantErr) {
+						t.Errorf("error = %v, want %v", err, test.wantErr)
+					}
+				case test.wantErrMsg != "":
+					if err == nil || err.Error() != test.wantErrMsg {
+						t.Errorf("error = %v, want %q", err, test.wantErrMsg)
+					}
+				case err != nil || mapper == nil:
+					t.Errorf("mapper = %v, error = %v, want a mapper", mapper, err)
+				}
+			},
+		)
+	}
+}
+
+func TestNewMapperFor(t *testing.T) {
+	if mapper, err := NewMapperFor[*mapperBody](); err != nil || mapper == nil {
+		t.Errorf("mapper = %v, error = %v, want a mapper", mapper, err)
+	}
+	if _, err := NewMapperFor[[]mapperBody](); err == nil {
+		t.Error("error = nil, want an error for a type parameter that is not a struct")
+	}
+}
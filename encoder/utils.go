@@ -0,0 +1,70 @@
+package encoder
+
+import (
+	"context"
+	"io"
+)
+
+// EncodeAndWriteContext encodes the body with the encoder and writes it to the writer, stopping once the context is
+// done. The encoders that do not implement ContextEncoder are checked before encoding and before writing
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - encoder: The encoder used to encode the body
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func EncodeAndWriteContext(
+	ctx context.Context,
+	encoder Encoder,
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	// Check the encoder
+	if encoder == nil {
+		return ErrNilEncoder
+	}
+
+	// Use the context support of the encoder if it has one
+	if contextEncoder, ok := encoder.(ContextEncoder); ok {
+		return contextEncoder.EncodeAndWriteContext(ctx, writer, beforeWriteFn, body)
+	}
+
+	// Check the context before encoding
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+	return encoder.EncodeAndWrite(writer, ContextBeforeWriteFn(ctx, beforeWriteFn), body)
+}
+
+// ContextBeforeWriteFn wraps the before write function so the context is checked before it is called, nothing is
+// written once the context is done
+//
+// Parameters:
+//
+//   - ctx: The context
+//   - beforeWriteFn: The function to call before writing, it can be nil
+//
+// Returns:
+//
+//   - func() error: The wrapped function, the given one as is if the context is never done
+func ContextBeforeWriteFn(ctx context.Context, beforeWriteFn func() error) func() error {
+	if ctx.Done() == nil {
+		return beforeWriteFn
+	}
+	return func() error {
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+		if beforeWriteFn != nil {
+			return beforeWriteFn()
+		}
+		return nil
+	}
+}
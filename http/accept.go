@@ -0,0 +1,130 @@
+package http
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+type (
+	// mediaRange is a media range of the Accept header with its quality value
+	mediaRange struct {
+		mainType string
+		subType  string
+		quality  float64
+	}
+)
+
+// parseAccept parses the Accept header into its media ranges, the malformed ones are ignored
+//
+// Parameters:
+//
+//   - accept: the Accept header value
+//
+// Returns:
+//
+//   - []mediaRange: the media ranges in the order they were given
+func parseAccept(accept string) []mediaRange {
+	var mediaRanges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Parse the media range and its parameters
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		mainType, subType, found := strings.Cut(mediaType, "/")
+		if !found || (mainType == "*" && subType != "*") {
+			continue
+		}
+
+		// Parse the quality value, it defaults to 1
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			parsedQuality, parseErr := strconv.ParseFloat(q, 64)
+			if parseErr != nil || parsedQuality < 0 || parsedQuality > 1 {
+				continue
+			}
+			quality = parsedQuality
+		}
+
+		mediaRanges = append(
+			mediaRanges, mediaRange{
+				mainType: mainType,
+				subType:  subType,
+				quality:  quality,
+			},
+		)
+	}
+	return mediaRanges
+}
+
+// specificity returns how specifically the media range matches the media type, or -1 if it does not match it
+//
+// Parameters:
+//
+//   - mediaType: the lowercase media type without parameters
+//
+// Returns:
+//
+//   - int: 2 for an exact match, 1 for a type/* match, 0 for a */* match and -1 if it does not match
+func (m mediaRange) specificity(mediaType string) int {
+	mainType, subType, _ := strings.Cut(mediaType, "/")
+	switch {
+	case m.mainType == "*":
+		return 0
+	case m.mainType != mainType:
+		return -1
+	case m.subType == "*":
+		return 1
+	case m.subType == subType:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// acceptQuality returns the quality value the media ranges give to the media type, taken from the most specific
+// media range that matches it
+//
+// Parameters:
+//
+//   - mediaRanges: the media ranges of the Accept header
+//   - mediaType: the lowercase media type without parameters
+//
+// Returns:
+//
+//   - float64: the quality value, 0 if no media range matches the media type
+func acceptQuality(mediaRanges []mediaRange, mediaType string) float64 {
+	bestSpecificity := -1
+	quality := 0.0
+	for _, mediaRange := range mediaRanges {
+		if specificity := mediaRange.specificity(mediaType); specificity > bestSpecificity {
+			bestSpecificity = specificity
+			quality = mediaRange.quality
+		}
+	}
+	return quality
+}
+
+// normalizeMediaType returns the lowercase media type without its parameters
+//
+// Parameters:
+//
+//   - contentType: the media type, as given in a Content-Type header
+//
+// Returns:
+//
+//   - string: the normalized media type
+//   - error: the error if the media type is malformed
+func normalizeMediaType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
@@ -0,0 +1,34 @@
+package http
+
+const (
+	// ContentTypeJSON is the default media type of the encoded bodies
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeProblemJSON is the media type of the RFC 9457 problem details
+	ContentTypeProblemJSON = "application/problem+json"
+
+	// HeaderContentType is the Content-Type header name
+	HeaderContentType = "Content-Type"
+
+	// HeaderAccept is the Accept header name
+	HeaderAccept = "Accept"
+
+	// HeaderVary is the Vary header name
+	HeaderVary = "Vary"
+
+	// HeaderContentEncoding is the Content-Encoding header name
+	HeaderContentEncoding = "Content-Encoding"
+
+	// HeaderContentTypeOptions is the X-Content-Type-Options header name
+	HeaderContentTypeOptions = "X-Content-Type-Options"
+
+	// EncodingIdentity is the content encoding of uncompressed bodies
+	EncodingIdentity = "identity"
+
+	// DefaultMaxBodySize is the default maximum size in bytes of a request body
+	DefaultMaxBodySize = 10 * 1024 * 1024
+
+	// DefaultMaxDrainSize is the maximum number of unread bytes discarded from a request body before closing it,
+	// larger leftovers are not drained, so the connection is not reused
+	DefaultMaxDrainSize = 256 * 1024
+)
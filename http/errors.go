@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+)
+
+const (
+	ErrUnsupportedMediaType       = "unsupported media type %q, expected one of %v"
+	ErrUnsupportedContentEncoding = "unsupported content encoding %q"
+	ErrNotAcceptable              = "none of the accepted media types %q is supported, expected one of %v"
+	ErrInvalidMediaType           = "invalid media type %q: %w"
+)
+
+var (
+	ErrNilResponseWriter  = errors.New("response writer is nil")
+	ErrNilRequest         = errors.New("request is nil")
+	ErrMissingContentType = errors.New("missing content type")
+	ErrInvalidSyntax      = errors.New("request body contains invalid JSON")
+	ErrInvalidBody        = errors.New("request body cannot be decoded")
+	ErrUnknownField       = errors.New("request body contains an unknown field")
+	ErrBodyTooLarge       = errors.New("request body is too large")
+	ErrNoEncoder          = errors.New("no encoder registered")
+	ErrNoDecoder          = errors.New("no decoder registered")
+)
+
+type (
+	// StatusError is the error returned by the HTTP helpers, it carries the HTTP status code of the response that
+	// should be sent to the client
+	StatusError struct {
+		StatusCode int
+		Err        error
+	}
+)
+
+// NewStatusError creates a new StatusError instance
+//
+// Parameters:
+//
+//   - statusCode: the HTTP status code
+//   - err: the wrapped error
+//
+// Returns:
+//
+//   - *StatusError: the new StatusError instance
+func NewStatusError(statusCode int, err error) *StatusError {
+	return &StatusError{
+		StatusCode: statusCode,
+		Err:        err,
+	}
+}
+
+// Error returns the error message
+//
+// Returns:
+//
+//   - string: the error message
+func (s StatusError) Error() string {
+	return s.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+//
+// Returns:
+//
+//   - error: the wrapped error
+func (s StatusError) Unwrap() error {
+	return s.Err
+}
+
+// StatusCode returns the HTTP status code carried by the error, 200 if the error is nil or 500 if it does not carry
+// one
+//
+// Parameters:
+//
+//   - err: the error
+//
+// Returns:
+//
+//   - int: the HTTP status code
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
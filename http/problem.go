@@ -0,0 +1,492 @@
+package http
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderdecompress "github.com/ralvarezdev/go-json/decoder/decompress"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsondecoderjsonseq "github.com/ralvarezdev/go-json/decoder/jsonseq"
+	gojsondecoderndjson "github.com/ralvarezdev/go-json/decoder/ndjson"
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+type (
+	// Problem is the RFC 9457 problem details object, the extension members are encoded next to the standard
+	// members. It implements the error interface, so handlers can return it directly
+	Problem struct {
+		// Type is the URI reference that identifies the problem type, empty means about:blank
+		Type string
+
+		// Title is the short summary of the problem type
+		Title string
+
+		// Status is the HTTP status code
+		Status int
+
+		// Detail is the explanation specific to this occurrence of the problem
+		Detail string
+
+		// Instance is the URI reference that identifies this occurrence of the problem
+		Instance string
+
+		// Pointer is the JSON Pointer to the request body member that caused the problem, encoded as the pointer
+		// extension member
+		Pointer string
+
+		// Extensions are the additional members of the problem
+		Extensions map[string]any
+	}
+
+	// problemError is an entry of the errors extension member, one for each collected decode error
+	problemError struct {
+		Pointer string `json:"pointer"`
+		Detail  string `json:"detail"`
+		Line    int    `json:"line,omitempty"`
+		Column  int    `json:"column,omitempty"`
+	}
+
+	// ProblemEncoder is the JSON encoder of the problem details, it advertises the application/problem+json media
+	// type
+	ProblemEncoder struct {
+		encoder *gojsonencoderjson.Encoder
+	}
+)
+
+const (
+	// problemMemberType is the type member name
+	problemMemberType = "type"
+
+	// problemMemberTitle is the title member name
+	problemMemberTitle = "title"
+
+	// problemMemberStatus is the status member name
+	problemMemberStatus = "status"
+
+	// problemMemberDetail is the detail member name
+	problemMemberDetail = "detail"
+
+	// problemMemberInstance is the instance member name
+	problemMemberInstance = "instance"
+
+	// problemMemberPointer is the pointer extension member name
+	problemMemberPointer = "pointer"
+)
+
+// NewProblem creates a new Problem instance, titled with the status text of the status code
+//
+// Parameters:
+//
+//   - status: the HTTP status code
+//   - detail: the explanation specific to this occurrence of the problem
+//
+// Returns:
+//
+//   - *Problem: the new Problem instance
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// SetExtension sets an extension member of the problem, the standard member names are ignored when encoding
+//
+// Parameters:
+//
+//   - key: the member name
+//   - value: the member value
+func (p *Problem) SetExtension(key string, value any) {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+}
+
+// Error returns the detail of the problem, or its title if it has no detail
+//
+// Returns:
+//
+//   - string: the error message
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// MarshalJSON encodes the problem as a single JSON object with the standard and the extension members
+//
+// Returns:
+//
+//   - []byte: the encoded problem
+//   - error: the error if any
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+6)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	// The standard members take precedence over the extension members
+	setMember := func(key, value string) {
+		if value != "" {
+			members[key] = value
+		} else {
+			delete(members, key)
+		}
+	}
+	setMember(problemMemberType, p.Type)
+	setMember(problemMemberTitle, p.Title)
+	setMember(problemMemberDetail, p.Detail)
+	setMember(problemMemberInstance, p.Instance)
+	setMember(problemMemberPointer, p.Pointer)
+	if p.Status != 0 {
+		members[problemMemberStatus] = p.Status
+	} else {
+		delete(members, problemMemberStatus)
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON decodes a problem, the members that are not standard are stored as extension members
+//
+// Parameters:
+//
+//   - data: the encoded problem
+//
+// Returns:
+//
+//   - error: the error if any
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	for key, value := range members {
+		var target any
+		switch key {
+		case problemMemberType:
+			target = &p.Type
+		case problemMemberTitle:
+			target = &p.Title
+		case problemMemberStatus:
+			target = &p.Status
+		case problemMemberDetail:
+			target = &p.Detail
+		case problemMemberInstance:
+			target = &p.Instance
+		case problemMemberPointer:
+			target = &p.Pointer
+		default:
+			var extension any
+			if err := json.Unmarshal(value, &extension); err != nil {
+				return err
+			}
+			p.SetExtension(key, extension)
+			continue
+		}
+
+		// RFC 9457 requires ignoring the standard members with the wrong type
+		_ = json.Unmarshal(value, target)
+	}
+	return nil
+}
+
+// NewProblemFromError maps an error to a problem. The errors that carry a status code and the errors caused by the
+// request body, such as the syntax, type, unknown field, strictness and limit errors, are reported with their
+// details, any other error is reported as an internal server error without details, even when a *DecodeError
+// locates it, so the internal failures are not exposed. The collected DecodeErrors are listed in the errors
+// extension member
+//
+// Parameters:
+//
+//   - err: the error
+//
+// Returns:
+//
+//   - *Problem: the problem, nil if the error is nil
+func NewProblemFromError(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	// Return the problem as is if the error already is one
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	// Get the status code of the error
+	status := http.StatusInternalServerError
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		status = statusErr.StatusCode
+	} else {
+		status = errorStatusCode(err)
+	}
+
+	// The details of the server errors are not exposed
+	if status >= http.StatusInternalServerError {
+		return NewProblem(status, "")
+	}
+	problem = NewProblem(status, err.Error())
+
+	// Add the location of the error in the request body
+	var decodeErr *gojsondecoder.DecodeError
+	var syntaxErr *json.SyntaxError
+	var lineErr *gojsondecoderndjson.LineError
+	var recordErr *gojsondecoderjsonseq.RecordError
+	if errors.As(err, &decodeErr) {
+		problem.Pointer = decodeErr.Path
+		if decodeErr.Offset >= 0 {
+			problem.SetExtension("offset", decodeErr.Offset)
+		}
+		if decodeErr.Line > 0 {
+			problem.SetExtension("line", decodeErr.Line)
+			problem.SetExtension("column", decodeErr.Column)
+		}
+	} else if errors.As(err, &syntaxErr) {
+		problem.SetExtension("offset", syntaxErr.Offset)
+	}
+	var decodeErrs gojsondecoder.DecodeErrors
+	if errors.As(err, &decodeErrs) && len(decodeErrs) > 1 {
+		problem.SetExtension("errors", newProblemErrors(decodeErrs))
+	}
+	if errors.As(err, &lineErr) {
+		problem.SetExtension("line", lineErr.Line)
+	}
+	if errors.As(err, &recordErr) {
+		problem.SetExtension("record", recordErr.Record)
+	}
+	return problem
+}
+
+// newProblemErrors creates the "errors" extension member of a problem with every collected decode error
+//
+// Parameters:
+//
+//   - decodeErrs: the collected decode errors
+//
+// Returns:
+//
+//   - []problemError: the errors of the extension member
+func newProblemErrors(decodeErrs gojsondecoder.DecodeErrors) []problemError {
+	problemErrs := make([]problemError, len(decodeErrs))
+	for i, decodeErr := range decodeErrs {
+		problemErrs[i] = problemError{
+			Pointer: decodeErr.Path,
+			Detail:  decodeErr.Err.Error(),
+			Line:    decodeErr.Line,
+			Column:  decodeErr.Column,
+		}
+	}
+	return problemErrs
+}
+
+// errorStatusCode returns the HTTP status code of an error that does not carry one, the errors that are not caused
+// by the request body, such as the read errors, are internal server errors
+//
+// Parameters:
+//
+//   - err: the error
+//
+// Returns:
+//
+//   - int: the HTTP status code
+func errorStatusCode(err error) int {
+	switch {
+	case isBodyTooLargeError(err):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, gojsondecoderdecompress.ErrUnknownContentEncoding):
+		return http.StatusUnsupportedMediaType
+	case isInvalidBodyError(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// isBodyTooLargeError returns whether the error is caused by a request body that exceeds a size or a resource limit
+//
+// Parameters:
+//
+//   - err: the error
+//
+// Returns:
+//
+//   - bool: whether the request body is too large
+func isBodyTooLargeError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	var limitErr *gojsondecoder.LimitError
+	return errors.As(err, &maxBytesErr) ||
+		errors.As(err, &limitErr) ||
+		errors.Is(err, gojsondecoderdecompress.ErrDecompressedTooLarge) ||
+		errors.Is(err, gojsondecoder.ErrRecordTooLarge)
+}
+
+// isInvalidBodyError returns whether the error is caused by the content of the request body, by the cause of the
+// decoding errors and not by the *DecodeError that locates them, as the decoders may also locate a server failure
+//
+// Parameters:
+//
+//   - err: the error
+//
+// Returns:
+//
+//   - bool: whether the request body is invalid
+func isInvalidBodyError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var corruptInputErr flate.CorruptInputError
+	switch {
+	// The missing and the truncated bodies
+	case errors.Is(err, gojsondecoder.ErrNilBody), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+
+	// The syntax, the type and the unknown field errors
+	case errors.As(err, &syntaxErr),
+		errors.As(err, &typeErr),
+		errors.Is(err, gojsondecoder.ErrInvalidSyntax),
+		errors.Is(err, gojsondecoder.ErrInvalidValue),
+		errors.Is(err, gojsondecoder.ErrUnknownField),
+		errors.Is(err, gojsondecoder.ErrValidation):
+		return true
+
+	// The strictness errors
+	case errors.Is(err, gojsondecoder.ErrDuplicateKey),
+		errors.Is(err, gojsondecoder.ErrTrailingData),
+		errors.Is(err, gojsondecoder.ErrInvalidUTF8):
+		return true
+
+	// The malformed arrays and sequences
+	case errors.Is(err, gojsondecoderjson.ErrExpectedArrayStart),
+		errors.Is(err, gojsondecoderjson.ErrExpectedArrayEnd),
+		errors.Is(err, gojsondecoderjsonseq.ErrMissingRecordSeparator),
+		errors.Is(err, gojsondecoderjsonseq.ErrTruncatedRecord):
+		return true
+
+	// The corrupted compressed bodies
+	case errors.Is(err, gzip.ErrHeader),
+		errors.Is(err, gzip.ErrChecksum),
+		errors.Is(err, zlib.ErrHeader),
+		errors.Is(err, zlib.ErrChecksum),
+		errors.As(err, &corruptInputErr):
+		return true
+	default:
+		return false
+	}
+}
+
+// NewProblemEncoder creates a new ProblemEncoder instance
+//
+// Returns:
+//
+//   - *ProblemEncoder: the new ProblemEncoder instance
+func NewProblemEncoder() *ProblemEncoder {
+	return &ProblemEncoder{
+		encoder: gojsonencoderjson.NewEncoder(nil),
+	}
+}
+
+// Encode encodes the problem
+//
+// Parameters:
+//
+//   - body: The problem to encode
+//
+// Returns:
+//
+//   - []byte: The encoded problem
+//   - error: The error if any
+func (p ProblemEncoder) Encode(
+	body any,
+) ([]byte, error) {
+	return p.encoder.Encode(body)
+}
+
+// EncodeAndWrite encodes the problem and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded problem to
+//   - beforeWriteFn: The function to call before writing the problem
+//   - body: The problem to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (p ProblemEncoder) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	return p.encoder.EncodeAndWrite(writer, beforeWriteFn, body)
+}
+
+// ContentType returns the media type of the problem details
+//
+// Returns:
+//
+//   - string: the media type
+func (p ProblemEncoder) ContentType() string {
+	return ContentTypeProblemJSON
+}
+
+// WriteProblem writes the problem to the response writer as application/problem+json, with its status code
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - problem: The problem to write
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteProblem(
+	responseWriter http.ResponseWriter,
+	problem *Problem,
+) error {
+	// Check if the problem is nil
+	if problem == nil {
+		return gojsonencoder.ErrNilBody
+	}
+
+	// Default to an internal server error if the status code is not set
+	status := problem.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	responder, err := NewResponder(NewProblemEncoder(), nil)
+	if err != nil {
+		return err
+	}
+	return responder.Write(responseWriter, status, problem)
+}
+
+// WriteError maps the error to a problem and writes it to the response writer
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - err: The error to write
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteError(
+	responseWriter http.ResponseWriter,
+	err error,
+) error {
+	return WriteProblem(responseWriter, NewProblemFromError(err))
+}
@@ -0,0 +1,271 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderdecompress "github.com/ralvarezdev/go-json/decoder/decompress"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+)
+
+// failingReader returns the data and then fails with the error
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if len(f.data) == 0 {
+		return 0, f.err
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestNewProblemFromError(t *testing.T) {
+	readErr := errors.New("read tcp 10.0.0.1:443: connection reset by peer")
+	decodeErr := func(decoder gojsondecoder.Decoder, reader io.Reader) error {
+		var dest struct {
+			ID   int `json:"id"`
+			Name any `json:"name"`
+		}
+		return decoder.DecodeReader(reader, &dest)
+	}
+	limitedDecoder := gojsondecoderjson.NewStreamDecoder(
+		gojsondecoderjson.NewOptions(gojsondecoderjson.WithLimits(gojsondecoder.NewLimits(0, 1, 0, 0, 0))),
+	)
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantDetail  bool
+		wantPointer string
+	}{
+		{
+			name:       "keeps the status code of the status errors",
+			err:        NewStatusError(http.StatusNotFound, errors.New("user not found")),
+			wantStatus: http.StatusNotFound,
+			wantDetail: true,
+		},
+		{
+			name:       "reports the located syntax errors",
+			err:        decodeErr(gojsondecoderjson.NewDecoder(nil), strings.NewReader(`{"name":}`)),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: true,
+		},
+		{
+			name:        "reports the located type errors",
+			err:         decodeErr(gojsondecoderjson.NewDecoder(nil), strings.NewReader(`{"id":"x"}`)),
+			wantStatus:  http.StatusBadRequest,
+			wantDetail:  true,
+			wantPointer: "/id",
+		},
+		{
+			name: "reports the unknown fields",
+			err: gojsondecoder.NewDecodeError(
+				"/unknown",
+				gojsondecoder.Position{Offset: 1},
+				nil,
+				fmt.Errorf(gojsondecoder.ErrTypedCause, gojsondecoder.ErrUnknownField, errors.New("unknown")),
+			),
+			wantStatus:  http.StatusBadRequest,
+			wantDetail:  true,
+			wantPointer: "/unknown",
+		},
+		{
+			name: "reports the strictness errors",
+			err: gojsondecoder.NewDecodeError(
+				"/name",
+				gojsondecoder.Position{Offset: 12},
+				nil,
+				gojsondecoder.ErrDuplicateKey,
+			),
+			wantStatus:  http.StatusBadRequest,
+			wantDetail:  true,
+			wantPointer: "/name",
+		},
+		{
+			name:       "reports the exceeded limits",
+			err:        decodeErr(limitedDecoder, strings.NewReader(`{"name":{"id":1}}`)),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantDetail: true,
+		},
+		{
+			name:       "reports the decompressed bodies that are too large",
+			err:        gojsondecoderdecompress.ErrDecompressedTooLarge,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantDetail: true,
+		},
+		{
+			name: "reports the unsupported content encodings",
+			err: fmt.Errorf(
+				gojsondecoderdecompress.ErrUnsupportedEncoding,
+				gojsondecoderdecompress.ErrUnknownContentEncoding,
+				"br",
+			),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantDetail: true,
+		},
+		{
+			name:       "reports the missing bodies",
+			err:        gojsondecoder.ErrNilBody,
+			wantStatus: http.StatusBadRequest,
+			wantDetail: true,
+		},
+		{
+			name: "hides the read errors of the stream decoder",
+			err: decodeErr(
+				gojsondecoderjson.NewStreamDecoder(nil),
+				&failingReader{data: []byte(`{"name":`), err: readErr},
+			),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "hides the read errors of the decoder",
+			err: decodeErr(
+				gojsondecoderjson.NewDecoder(nil),
+				&failingReader{data: []byte(`{"name":`), err: readErr},
+			),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "hides the located server errors",
+			err: gojsondecoder.NewDecodeError(
+				"/file",
+				gojsondecoder.Position{Offset: 1},
+				nil,
+				errors.New("field not handled on decoding: File"),
+			),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "hides the other errors",
+			err:        errors.New("database is unavailable"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				problem := NewProblemFromError(test.err)
+				if problem == nil {
+					t.Fatalf("problem = nil, want a problem for %v", test.err)
+				}
+				if problem.Status != test.wantStatus {
+					t.Errorf("status = %d, want %d for %v", problem.Status, test.wantStatus, test.err)
+				}
+				if problem.Title != http.StatusText(test.wantStatus) {
+					t.Errorf("title = %q, want %q", problem.Title, http.StatusText(test.wantStatus))
+				}
+
+				// Check the details are only exposed for the client errors
+				if test.wantDetail && problem.Detail != test.err.Error() {
+					t.Errorf("detail = %q, want %q", problem.Detail, test.err.Error())
+				}
+				if !test.wantDetail && problem.Detail != "" {
+					t.Errorf("detail = %q, want it hidden", problem.Detail)
+				}
+				if problem.Pointer != test.wantPointer {
+					t.Errorf("pointer = %q, want %q", problem.Pointer, test.wantPointer)
+				}
+			},
+		)
+	}
+}
+
+func TestNewProblemFromErrorCollected(t *testing.T) {
+	err := gojsondecoder.NewDecodeErrors(
+		gojsondecoder.NewDecodeError(
+			"/name",
+			gojsondecoder.Position{Offset: 9, Line: 1, Column: 10},
+			nil,
+			gojsondecoder.ErrDuplicateKey,
+		),
+		gojsondecoder.NewDecodeError(
+			"/id",
+			gojsondecoder.Position{Offset: 20, Line: 2, Column: 3},
+			nil,
+			gojsondecoder.ErrUnknownField,
+		),
+	)
+	problem := NewProblemFromError(err)
+	if problem.Status != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", problem.Status, http.StatusBadRequest)
+	}
+
+	// Every collected error is listed in the errors extension member
+	problemErrs, ok := problem.Extensions["errors"].([]problemError)
+	if !ok || len(problemErrs) != 2 {
+		t.Fatalf("errors = %v, want 2 entries", problem.Extensions["errors"])
+	}
+	if problemErrs[0].Pointer != "/name" || problemErrs[1].Pointer != "/id" || problemErrs[1].Line != 2 {
+		t.Errorf("errors = %+v, want the /name and /id entries", problemErrs)
+	}
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem *Problem
+		want    string
+	}{
+		{
+			name:    "omits the empty standard members",
+			problem: NewProblem(http.StatusBadRequest, ""),
+			want:    `{"status":400,"title":"Bad Request"}`,
+		},
+		{
+			name: "encodes the extension members next to the standard members",
+			problem: &Problem{
+				Type:       "https://example.com/problems/invalid-body",
+				Status:     http.StatusBadRequest,
+				Detail:     "invalid body",
+				Pointer:    "/name",
+				Extensions: map[string]any{"line": 1},
+			},
+			want: `{"detail":"invalid body","line":1,"pointer":"/name","status":400,` +
+				`"type":"https://example.com/problems/invalid-body"}`,
+		},
+		{
+			name: "gives the standard members precedence over the extension members",
+			problem: &Problem{
+				Title:      "Not Found",
+				Status:     http.StatusNotFound,
+				Extensions: map[string]any{"status": "ignored", "title": "ignored", "detail": "ignored"},
+			},
+			want: `{"status":404,"title":"Not Found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				got, err := json.Marshal(test.problem)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != test.want {
+					t.Errorf("encoded = %s, want %s", got, test.want)
+				}
+
+				// The decoded problem keeps the members
+				var decoded Problem
+				if err = json.Unmarshal(got, &decoded); err != nil {
+					t.Fatal(err)
+				}
+				if decoded.Status != test.problem.Status || decoded.Pointer != test.problem.Pointer {
+					t.Errorf("decoded = %+v, want %+v", decoded, *test.problem)
+				}
+			},
+		)
+	}
+}
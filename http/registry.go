@@ -0,0 +1,445 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+)
+
+type (
+	// registeredEncoder is an encoder registered for a media type
+	registeredEncoder struct {
+		mediaType string
+		encoder   gojsonencoder.Encoder
+	}
+
+	// registeredDecoder is a decoder registered for a media type
+	registeredDecoder struct {
+		mediaType string
+		decoder   gojsondecoder.Decoder
+	}
+
+	// Registry maps media types to encoders and decoders, and selects them from the Accept and Content-Type headers.
+	// It implements the Encoder and Decoder interfaces with the first registered encoder and decoder, so it can be
+	// used wherever a single encoder or decoder is expected
+	Registry struct {
+		mutex    sync.RWMutex
+		encoders []registeredEncoder
+		decoders []registeredDecoder
+	}
+)
+
+// NewRegistry creates a new empty Registry instance
+//
+// Returns:
+//
+//   - *Registry: the new Registry instance
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// RegisterEncoder registers the encoder for the media type, replacing the encoder previously registered for it. The
+// first registered encoder is the default one
+//
+// Parameters:
+//
+//   - mediaType: the media type, empty uses the one advertised by the encoder or ContentTypeJSON
+//   - encoder: the encoder
+//
+// Returns:
+//
+//   - error: the error if the encoder is nil or the media type is malformed
+func (r *Registry) RegisterEncoder(
+	mediaType string,
+	encoder gojsonencoder.Encoder,
+) error {
+	// Check if the encoder is nil
+	if encoder == nil {
+		return gojsonencoder.ErrNilEncoder
+	}
+
+	// Normalize the media type
+	if mediaType == "" {
+		mediaType = EncoderContentType(encoder)
+	}
+	normalizedMediaType, err := normalizeMediaType(mediaType)
+	if err != nil {
+		return fmt.Errorf(ErrInvalidMediaType, mediaType, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Replace the encoder if the media type is already registered
+	entry := registeredEncoder{mediaType: normalizedMediaType, encoder: encoder}
+	index := slices.IndexFunc(
+		r.encoders, func(registered registeredEncoder) bool {
+			return registered.mediaType == normalizedMediaType
+		},
+	)
+	if index >= 0 {
+		r.encoders[index] = entry
+	} else {
+		r.encoders = append(r.encoders, entry)
+	}
+	return nil
+}
+
+// RegisterDecoder registers the decoder for the media type, replacing the decoder previously registered for it. The
+// first registered decoder is the default one
+//
+// Parameters:
+//
+//   - mediaType: the media type, empty uses the one advertised by the decoder or ContentTypeJSON
+//   - decoder: the decoder
+//
+// Returns:
+//
+//   - error: the error if the decoder is nil or the media type is malformed
+func (r *Registry) RegisterDecoder(
+	mediaType string,
+	decoder gojsondecoder.Decoder,
+) error {
+	// Check if the decoder is nil
+	if decoder == nil {
+		return gojsondecoder.ErrNilDecoder
+	}
+
+	// Normalize the media type
+	if mediaType == "" {
+		mediaType = DecoderContentType(decoder)
+	}
+	normalizedMediaType, err := normalizeMediaType(mediaType)
+	if err != nil {
+		return fmt.Errorf(ErrInvalidMediaType, mediaType, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Replace the decoder if the media type is already registered
+	entry := registeredDecoder{mediaType: normalizedMediaType, decoder: decoder}
+	index := slices.IndexFunc(
+		r.decoders, func(registered registeredDecoder) bool {
+			return registered.mediaType == normalizedMediaType
+		},
+	)
+	if index >= 0 {
+		r.decoders[index] = entry
+	} else {
+		r.decoders = append(r.decoders, entry)
+	}
+	return nil
+}
+
+// ResolveEncoder selects the encoder that best matches the Accept header, honouring the quality values and the
+// wildcards. Ties are broken by the registration order, and a missing Accept header selects the default encoder
+//
+// Parameters:
+//
+//   - accept: the Accept header value
+//
+// Returns:
+//
+//   - string: the media type of the selected encoder
+//   - gojsonencoder.Encoder: the selected encoder
+//   - error: a *StatusError with the 406 status code if no registered encoder is acceptable
+func (r *Registry) ResolveEncoder(accept string) (string, gojsonencoder.Encoder, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Check if there are registered encoders
+	if len(r.encoders) == 0 {
+		return "", nil, NewStatusError(http.StatusInternalServerError, ErrNoEncoder)
+	}
+
+	// Select the default encoder if there is no Accept header
+	if accept == "" {
+		return r.encoders[0].mediaType, r.encoders[0].encoder, nil
+	}
+
+	// Select the registered encoder with the highest quality value
+	mediaRanges := parseAccept(accept)
+	bestIndex := -1
+	bestQuality := 0.0
+	for i, registered := range r.encoders {
+		if quality := acceptQuality(mediaRanges, registered.mediaType); quality > bestQuality {
+			bestIndex = i
+			bestQuality = quality
+		}
+	}
+	if bestIndex < 0 {
+		return "", nil, NewStatusError(
+			http.StatusNotAcceptable,
+			fmt.Errorf(ErrNotAcceptable, accept, r.encoderMediaTypes()),
+		)
+	}
+	return r.encoders[bestIndex].mediaType, r.encoders[bestIndex].encoder, nil
+}
+
+// ResolveDecoder selects the decoder registered for the media type of the Content-Type header, its parameters are
+// ignored
+//
+// Parameters:
+//
+//   - contentType: the Content-Type header value
+//
+// Returns:
+//
+//   - string: the media type of the selected decoder
+//   - gojsondecoder.Decoder: the selected decoder
+//   - error: a *StatusError with the 415 status code if the media type is missing or not registered
+func (r *Registry) ResolveDecoder(contentType string) (string, gojsondecoder.Decoder, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Check if there are registered decoders
+	if len(r.decoders) == 0 {
+		return "", nil, NewStatusError(http.StatusInternalServerError, ErrNoDecoder)
+	}
+
+	// Check if the content type is missing
+	if contentType == "" {
+		return "", nil, NewStatusError(http.StatusUnsupportedMediaType, ErrMissingContentType)
+	}
+
+	// Select the decoder registered for the media type
+	mediaType, err := normalizeMediaType(contentType)
+	if err == nil {
+		for _, registered := range r.decoders {
+			if registered.mediaType == mediaType {
+				return registered.mediaType, registered.decoder, nil
+			}
+		}
+	}
+	return "", nil, NewStatusError(
+		http.StatusUnsupportedMediaType,
+		fmt.Errorf(ErrUnsupportedMediaType, contentType, r.decoderMediaTypes()),
+	)
+}
+
+// Write selects the encoder from the Accept header of the request, and writes the encoded body to the response
+// writer with the given status code, until the request context is done
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - request: The HTTP request
+//   - statusCode: The status code of the response
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, a *StatusError with the 406 status code if no registered encoder is acceptable
+func (r *Registry) Write(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+	statusCode int,
+	body any,
+) error {
+	// Check if the response writer is nil
+	if responseWriter == nil {
+		return ErrNilResponseWriter
+	}
+
+	// Check if the request is nil
+	if request == nil {
+		return NewStatusError(http.StatusInternalServerError, ErrNilRequest)
+	}
+
+	// Select the encoder, the response varies with the Accept header
+	responseWriter.Header().Add(HeaderVary, HeaderAccept)
+	mediaType, encoder, err := r.ResolveEncoder(request.Header.Get(HeaderAccept))
+	if err != nil {
+		return err
+	}
+
+	// Write the response
+	responder, err := NewResponder(encoder, NewResponderOptions(mediaType, nil))
+	if err != nil {
+		return err
+	}
+	return responder.WriteContext(request.Context(), responseWriter, statusCode, body)
+}
+
+// DecodeRequest selects the decoder from the Content-Type header of the request, and decodes its body into the
+// destination
+//
+// Parameters:
+//
+//   - responseWriter: The HTTP response writer of the request
+//   - request: The HTTP request
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, a *StatusError carrying the HTTP status code of the response
+func (r *Registry) DecodeRequest(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+	dest any,
+) error {
+	// Check if the request is nil
+	if request == nil {
+		return NewStatusError(http.StatusInternalServerError, ErrNilRequest)
+	}
+
+	// Select the decoder
+	mediaType, decoder, err := r.ResolveDecoder(request.Header.Get(HeaderContentType))
+	if err != nil {
+		return err
+	}
+
+	// Decode the request body
+	requestDecoder, err := NewRequestDecoder(decoder, NewRequestDecoderOptions([]string{mediaType}, 0))
+	if err != nil {
+		return NewStatusError(http.StatusInternalServerError, err)
+	}
+	return requestDecoder.DecodeRequest(responseWriter, request, dest)
+}
+
+// Encode encodes the body with the default encoder
+//
+// Parameters:
+//
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - []byte: The encoded body
+//   - error: The error if any
+func (r *Registry) Encode(
+	body any,
+) ([]byte, error) {
+	encoder, err := r.defaultEncoder()
+	if err != nil {
+		return nil, err
+	}
+	return encoder.Encode(body)
+}
+
+// EncodeAndWrite encodes the body with the default encoder and writes it to the writer
+//
+// Parameters:
+//
+//   - writer: The writer to write the encoded body to
+//   - beforeWriteFn: The function to call before writing the body
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Registry) EncodeAndWrite(
+	writer io.Writer,
+	beforeWriteFn func() error,
+	body any,
+) error {
+	encoder, err := r.defaultEncoder()
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeAndWrite(writer, beforeWriteFn, body)
+}
+
+// Decode decodes the body with the default decoder and stores it in the destination
+//
+// Parameters:
+//
+//   - body: The body to decode
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Registry) Decode(
+	body any,
+	dest any,
+) error {
+	decoder, err := r.defaultDecoder()
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(body, dest)
+}
+
+// DecodeReader decodes the body from a reader with the default decoder and stores it in the destination
+//
+// Parameters:
+//
+//   - reader: The reader to read the body from
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any
+func (r *Registry) DecodeReader(
+	reader io.Reader,
+	dest any,
+) error {
+	decoder, err := r.defaultDecoder()
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeReader(reader, dest)
+}
+
+// defaultEncoder returns the first registered encoder
+//
+// Returns:
+//
+//   - gojsonencoder.Encoder: the default encoder
+//   - error: the error if no encoder is registered
+func (r *Registry) defaultEncoder() (gojsonencoder.Encoder, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.encoders) == 0 {
+		return nil, ErrNoEncoder
+	}
+	return r.encoders[0].encoder, nil
+}
+
+// defaultDecoder returns the first registered decoder
+//
+// Returns:
+//
+//   - gojsondecoder.Decoder: the default decoder
+//   - error: the error if no decoder is registered
+func (r *Registry) defaultDecoder() (gojsondecoder.Decoder, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.decoders) == 0 {
+		return nil, ErrNoDecoder
+	}
+	return r.decoders[0].decoder, nil
+}
+
+// encoderMediaTypes returns the media types of the registered encoders, the read lock must be held
+//
+// Returns:
+//
+//   - []string: the media types
+func (r *Registry) encoderMediaTypes() []string {
+	mediaTypes := make([]string, len(r.encoders))
+	for i, registered := range r.encoders {
+		mediaTypes[i] = registered.mediaType
+	}
+	return mediaTypes
+}
+
+// decoderMediaTypes returns the media types of the registered decoders, the read lock must be held
+//
+// Returns:
+//
+//   - []string: the media types
+func (r *Registry) decoderMediaTypes() []string {
+	mediaTypes := make([]string, len(r.decoders))
+	for i, registered := range r.decoders {
+		mediaTypes[i] = registered.mediaType
+	}
+	return mediaTypes
+}
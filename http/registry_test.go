@@ -0,0 +1,177 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+// newTestRegistry creates a registry with the JSON encoder and decoder registered for the JSON and NDJSON media
+// types, in that order
+func newTestRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	for _, mediaType := range []string{ContentTypeJSON, "application/x-ndjson"} {
+		if err := registry.RegisterEncoder(mediaType, gojsonencoderjson.NewEncoder(nil)); err != nil {
+			t.Fatalf("RegisterEncoder(%q) error = %v", mediaType, err)
+		}
+		if err := registry.RegisterDecoder(mediaType, gojsondecoderjson.NewDecoder(nil)); err != nil {
+			t.Fatalf("RegisterDecoder(%q) error = %v", mediaType, err)
+		}
+	}
+	return registry
+}
+
+func TestRegistryResolveEncoder(t *testing.T) {
+	tests := []struct {
+		name           string
+		accept         string
+		wantMediaType  string
+		wantStatusCode int
+	}{
+		{
+			name:          "selects the default encoder without an Accept header",
+			wantMediaType: ContentTypeJSON,
+		},
+		{
+			name:          "selects the exact media type",
+			accept:        "application/x-ndjson",
+			wantMediaType: "application/x-ndjson",
+		},
+		{
+			name:          "selects the highest quality value",
+			accept:        "application/json;q=0.5, application/x-ndjson;q=0.8",
+			wantMediaType: "application/x-ndjson",
+		},
+		{
+			name:          "breaks the ties by the registration order",
+			accept:        "application/x-ndjson, application/json",
+			wantMediaType: ContentTypeJSON,
+		},
+		{
+			name:          "selects the default encoder for the wildcard",
+			accept:        "*/*",
+			wantMediaType: ContentTypeJSON,
+		},
+		{
+			name:          "prefers the most specific media range",
+			accept:        "application/*;q=0.9, application/json;q=0.1",
+			wantMediaType: "application/x-ndjson",
+		},
+		{
+			name:           "rejects the media types excluded with a zero quality value",
+			accept:         "application/*;q=0",
+			wantStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:           "rejects the unregistered media types",
+			accept:         "text/html",
+			wantStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:          "ignores the malformed media ranges",
+			accept:        "application, */json, application/x-ndjson;q=2, application/json",
+			wantMediaType: ContentTypeJSON,
+		},
+	}
+
+	registry := newTestRegistry(t)
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				mediaType, encoder, err := registry.ResolveEncoder(test.accept)
+				if test.wantStatusCode != 0 {
+					if StatusCode(err) != test.wantStatusCode {
+						t.Fatalf("error = %v, want the %d status code", err, test.wantStatusCode)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				if mediaType != test.wantMediaType || encoder == nil {
+					t.Errorf("media type = %q, want %q", mediaType, test.wantMediaType)
+				}
+			},
+		)
+	}
+}
+
+func TestRegistryResolveDecoder(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		wantMediaType  string
+		wantStatusCode int
+	}{
+		{
+			name:          "selects the registered media type",
+			contentType:   "application/x-ndjson",
+			wantMediaType: "application/x-ndjson",
+		},
+		{
+			name:          "ignores the parameters and the case",
+			contentType:   "Application/JSON; charset=utf-8",
+			wantMediaType: ContentTypeJSON,
+		},
+		{
+			name:           "rejects the missing content type",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "rejects the unregistered media types",
+			contentType:    "text/plain",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "rejects the malformed media types",
+			contentType:    "application/",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	registry := newTestRegistry(t)
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				mediaType, decoder, err := registry.ResolveDecoder(test.contentType)
+				if test.wantStatusCode != 0 {
+					if StatusCode(err) != test.wantStatusCode {
+						t.Fatalf("error = %v, want the %d status code", err, test.wantStatusCode)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				if mediaType != test.wantMediaType || decoder == nil {
+					t.Errorf("media type = %q, want %q", mediaType, test.wantMediaType)
+				}
+			},
+		)
+	}
+}
+
+func TestRegistryWrite(t *testing.T) {
+	registry := newTestRegistry(t)
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(HeaderAccept, "application/x-ndjson")
+	recorder := httptest.NewRecorder()
+	if err := registry.Write(recorder, request, http.StatusOK, map[string]int{"id": 1}); err != nil {
+		t.Fatalf("error = %v, want nil", err)
+	}
+
+	// The response carries the selected media type and varies with the Accept header
+	if contentType := recorder.Header().Get(HeaderContentType); contentType != "application/x-ndjson" {
+		t.Errorf("content type = %q, want %q", contentType, "application/x-ndjson")
+	}
+	if vary := recorder.Header().Get(HeaderVary); vary != HeaderAccept {
+		t.Errorf("vary = %q, want %q", vary, HeaderAccept)
+	}
+	if body := strings.TrimSpace(recorder.Body.String()); body != `{"id":1}` {
+		t.Errorf("body = %q, want %q", body, `{"id":1}`)
+	}
+}
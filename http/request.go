@@ -0,0 +1,285 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderdecompress "github.com/ralvarezdev/go-json/decoder/decompress"
+)
+
+type (
+	// encodingDecoder is the interface implemented by the decoders that can decompress the body according to its
+	// content encoding, such as the decompressing decoder
+	encodingDecoder interface {
+		DecodeReaderWithEncodingContext(
+			ctx context.Context,
+			reader io.Reader,
+			contentEncoding string,
+			dest any,
+		) error
+	}
+
+	// RequestDecoder decodes HTTP request bodies with a decoder, enforcing the content type and the body size
+	RequestDecoder struct {
+		decoder      gojsondecoder.Decoder
+		contentTypes []string
+		maxBodySize  int64
+	}
+
+	// RequestDecoderOptions are the additional settings for the request decoder
+	RequestDecoderOptions struct {
+		// contentTypes are the accepted media types of the request body
+		contentTypes []string
+
+		// maxBodySize is the maximum size in bytes of the request body
+		maxBodySize int64
+	}
+)
+
+// NewRequestDecoderOptions creates a new RequestDecoderOptions instance
+//
+// Parameters:
+//
+//   - contentTypes: the accepted media types of the request body, empty accepts the one advertised by the decoder or
+//     ContentTypeJSON
+//   - maxBodySize: the maximum size in bytes of the request body, 0 uses DefaultMaxBodySize and a negative value
+//     disables the limit
+//
+// Returns:
+//
+//   - *RequestDecoderOptions: the new RequestDecoderOptions instance
+func NewRequestDecoderOptions(
+	contentTypes []string,
+	maxBodySize int64,
+) *RequestDecoderOptions {
+	return &RequestDecoderOptions{
+		contentTypes: contentTypes,
+		maxBodySize:  maxBodySize,
+	}
+}
+
+// NewRequestDecoder creates a new RequestDecoder instance
+//
+// Parameters:
+//
+//   - decoder: the decoder used to decode the request bodies
+//   - options: the additional settings for the request decoder
+//
+// Returns:
+//
+//   - *RequestDecoder: the new RequestDecoder instance
+//   - error: the error if the decoder is nil
+func NewRequestDecoder(
+	decoder gojsondecoder.Decoder,
+	options *RequestDecoderOptions,
+) (*RequestDecoder, error) {
+	// Check if the decoder is nil
+	if decoder == nil {
+		return nil, gojsondecoder.ErrNilDecoder
+	}
+
+	// Initialize the settings
+	var contentTypes []string
+	var maxBodySize int64
+	if options != nil {
+		contentTypes = options.contentTypes
+		maxBodySize = options.maxBodySize
+	}
+
+	// Initialize the accepted media types from the decoder
+	if len(contentTypes) == 0 {
+		contentTypes = []string{DecoderContentType(decoder)}
+	}
+
+	// Initialize the maximum body size
+	if maxBodySize == 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	return &RequestDecoder{
+		decoder:      decoder,
+		contentTypes: contentTypes,
+		maxBodySize:  maxBodySize,
+	}, nil
+}
+
+// DecoderContentType returns the media type advertised by the decoder, or ContentTypeJSON if it does not
+// advertise one
+//
+// Parameters:
+//
+//   - decoder: the decoder
+//
+// Returns:
+//
+//   - string: the media type
+func DecoderContentType(decoder gojsondecoder.Decoder) string {
+	if contentTypeDecoder, ok := decoder.(gojsondecoder.ContentTypeDecoder); ok {
+		if contentType := contentTypeDecoder.ContentType(); contentType != "" {
+			return contentType
+		}
+	}
+	return ContentTypeJSON
+}
+
+// DecodeRequest checks the content type of the request and decodes its body into the destination, until the
+// request context is done. The body is drained and closed afterwards so the connection can be reused, while a body
+// exceeding the maximum body size makes the server close the connection through the response writer
+//
+// Parameters:
+//
+//   - responseWriter: The HTTP response writer of the request
+//   - request: The HTTP request
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, a *StatusError carrying the HTTP status code of the response
+func (r RequestDecoder) DecodeRequest(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+	dest any,
+) error {
+	// Check if the request is nil
+	if request == nil {
+		return NewStatusError(http.StatusInternalServerError, ErrNilRequest)
+	}
+
+	// Check if the body is empty
+	if request.Body == nil || request.Body == http.NoBody {
+		return NewStatusError(http.StatusBadRequest, gojsondecoder.ErrNilBody)
+	}
+	defer drainAndClose(request.Body)
+
+	// Check the content type
+	if err := r.checkContentType(request.Header.Get(HeaderContentType)); err != nil {
+		return NewStatusError(http.StatusUnsupportedMediaType, err)
+	}
+
+	// Limit the body size
+	var reader io.Reader = request.Body
+	if r.maxBodySize > 0 {
+		reader = http.MaxBytesReader(responseWriter, request.Body, r.maxBodySize)
+	}
+
+	// Decode the body, decompressing it if the decoder supports the content encoding
+	var err error
+	contentEncoding := request.Header.Get(HeaderContentEncoding)
+	if contentEncodingDecoder, ok := r.decoder.(encodingDecoder); ok {
+		err = contentEncodingDecoder.DecodeReaderWithEncodingContext(request.Context(), reader, contentEncoding, dest)
+	} else if contentEncoding != "" && !strings.EqualFold(contentEncoding, EncodingIdentity) {
+		return NewStatusError(
+			http.StatusUnsupportedMediaType,
+			fmt.Errorf(ErrUnsupportedContentEncoding, contentEncoding),
+		)
+	} else {
+		err = gojsondecoder.DecodeReaderContext(request.Context(), r.decoder, reader, dest)
+	}
+	if err != nil {
+		return newDecodeStatusError(err)
+	}
+	return nil
+}
+
+// checkContentType checks if the media type of the Content-Type header is accepted, its parameters are ignored
+//
+// Parameters:
+//
+//   - contentType: The Content-Type header value
+//
+// Returns:
+//
+//   - error: The error if the media type is missing or not accepted
+func (r RequestDecoder) checkContentType(contentType string) error {
+	if contentType == "" {
+		return ErrMissingContentType
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.ContainsFunc(
+		r.contentTypes, func(accepted string) bool {
+			return strings.EqualFold(accepted, mediaType)
+		},
+	) {
+		return fmt.Errorf(ErrUnsupportedMediaType, contentType, r.contentTypes)
+	}
+	return nil
+}
+
+// DecodeRequest checks the content type of the request and decodes its body into the destination with the decoder,
+// using the default request decoder settings
+//
+// Parameters:
+//
+//   - responseWriter: The HTTP response writer of the request
+//   - request: The HTTP request
+//   - decoder: The decoder used to decode the body
+//   - dest: The destination to store the decoded body
+//
+// Returns:
+//
+//   - error: The error if any, a *StatusError carrying the HTTP status code of the response
+func DecodeRequest(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+	decoder gojsondecoder.Decoder,
+	dest any,
+) error {
+	requestDecoder, err := NewRequestDecoder(decoder, nil)
+	if err != nil {
+		return NewStatusError(http.StatusInternalServerError, err)
+	}
+	return requestDecoder.DecodeRequest(responseWriter, request, dest)
+}
+
+// newDecodeStatusError maps a decoding error to a StatusError carrying the matching HTTP status code, the errors that
+// are not caused by the request body, such as the read errors, are internal server errors
+//
+// Parameters:
+//
+//   - err: The decoding error
+//
+// Returns:
+//
+//   - *StatusError: The status error, wrapping both the typed error and the decoding error
+func newDecodeStatusError(err error) *StatusError {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return NewStatusError(http.StatusRequestTimeout, err)
+	case isBodyTooLargeError(err):
+		return NewStatusError(http.StatusRequestEntityTooLarge, fmt.Errorf("%w: %w", ErrBodyTooLarge, err))
+	case errors.Is(err, gojsondecoderdecompress.ErrUnknownContentEncoding):
+		return NewStatusError(http.StatusUnsupportedMediaType, err)
+	case errors.Is(err, gojsondecoder.ErrNilBody), errors.Is(err, io.EOF):
+		return NewStatusError(http.StatusBadRequest, gojsondecoder.ErrNilBody)
+	case errors.Is(err, gojsondecoder.ErrUnknownField):
+		return NewStatusError(http.StatusBadRequest, fmt.Errorf("%w: %w", ErrUnknownField, err))
+	case errors.As(err, &syntaxErr),
+		errors.Is(err, gojsondecoder.ErrInvalidSyntax),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return NewStatusError(http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidSyntax, err))
+	case isInvalidBodyError(err):
+		return NewStatusError(http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidBody, err))
+	default:
+		return NewStatusError(http.StatusInternalServerError, err)
+	}
+}
+
+// drainAndClose discards up to DefaultMaxDrainSize unread bytes from the body and closes it, so the keep-alive
+// connection can be reused
+//
+// Parameters:
+//
+//   - body: The request body
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, DefaultMaxDrainSize))
+	_ = body.Close()
+}
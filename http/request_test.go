@@ -0,0 +1,142 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+	gojsondecoderjson "github.com/ralvarezdev/go-json/decoder/json"
+	gojsondecoderprotojson "github.com/ralvarezdev/go-json/decoder/protojson"
+)
+
+type requestBody struct {
+	Name string                            `json:"name"`
+	File *descriptorpb.FileDescriptorProto `json:"file"`
+}
+
+func TestDecodeRequest(t *testing.T) {
+	strictOptions := gojsondecoder.NewStrictOptions(true, gojsondecoder.DuplicateKeysLastWins, false, false)
+	jsonDecoder := gojsondecoderjson.NewDecoder(
+		gojsondecoderjson.NewOptions(gojsondecoderjson.WithStrictOptions(strictOptions)),
+	)
+	protoJSONDecoder := gojsondecoderprotojson.NewDecoder(
+		gojsondecoderprotojson.NewOptions(false, gojsondecoderprotojson.WithStrictOptions(strictOptions)),
+	)
+
+	tests := []struct {
+		name           string
+		decoder        gojsondecoder.Decoder
+		body           string
+		wantStatusCode int
+		wantErr        error
+	}{
+		{
+			name:    "decodes the body",
+			decoder: jsonDecoder,
+			body:    `{"name":"x"}`,
+		},
+		{
+			name:           "rejects the unknown fields",
+			decoder:        jsonDecoder,
+			body:           `{"name":"x","unknown":1}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrUnknownField,
+		},
+		{
+			name:           "rejects the invalid syntax",
+			decoder:        jsonDecoder,
+			body:           `{"name":}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrInvalidSyntax,
+		},
+		{
+			name:           "rejects the type mismatches",
+			decoder:        jsonDecoder,
+			body:           `{"name":1}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrInvalidBody,
+		},
+		{
+			name:           "rejects the unknown fields of the proto messages",
+			decoder:        protoJSONDecoder,
+			body:           `{"name":"x","file":{"unknown":1}}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrUnknownField,
+		},
+		{
+			name:           "rejects the invalid syntax of the proto messages",
+			decoder:        protoJSONDecoder,
+			body:           `{"name":"x","file":{"name":}}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrInvalidSyntax,
+		},
+		{
+			name:           "rejects the invalid values of the proto messages",
+			decoder:        protoJSONDecoder,
+			body:           `{"name":"x","file":{"name":1}}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        ErrInvalidBody,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+				request.Header.Set(HeaderContentType, ContentTypeJSON)
+				var dest requestBody
+				err := DecodeRequest(httptest.NewRecorder(), request, test.decoder, &dest)
+				if test.wantErr == nil {
+					if err != nil {
+						t.Fatalf("error = %v, want nil", err)
+					}
+					return
+				}
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("error = %v, want %v", err, test.wantErr)
+				}
+				if got := StatusCode(err); got != test.wantStatusCode {
+					t.Errorf("status code = %d, want %d", got, test.wantStatusCode)
+				}
+			},
+		)
+	}
+}
+
+func TestRequestDecoderDecodeRequestMaxBodySize(t *testing.T) {
+	requestDecoder, err := NewRequestDecoder(
+		gojsondecoderjson.NewDecoder(nil),
+		NewRequestDecoderOptions([]string{ContentTypeJSON}, 16),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var dest requestBody
+				w.WriteHeader(StatusCode(requestDecoder.DecodeRequest(w, r, &dest)))
+			},
+		),
+	)
+	defer server.Close()
+
+	response, err := http.Post(server.URL, ContentTypeJSON, strings.NewReader(`{"name":"`+strings.Repeat("x", 64)+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	// The oversized body is rejected and the server is told to close the connection
+	if response.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code = %d, want %d", response.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+	if !response.Close {
+		t.Error("connection kept alive, want it closed after the oversized body")
+	}
+}
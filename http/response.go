@@ -0,0 +1,371 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+type (
+	// ErrorBodyFn builds the status code and the body of the fallback response written when the encoding fails
+	// before any byte is written
+	ErrorBodyFn func(err error) (int, any)
+
+	// Responder writes HTTP responses encoded with an encoder, setting the content type and the status code
+	Responder struct {
+		encoder     gojsonencoder.Encoder
+		contentType string
+		errorBodyFn ErrorBodyFn
+	}
+
+	// deferredResponseWriter is the response writer that delays the status code until the first byte of the body is
+	// written or flushed, so the fallback error response can still replace the response if the encoding fails before
+	deferredResponseWriter struct {
+		http.ResponseWriter
+		statusCode int
+		committed  bool
+	}
+
+	// ResponderOptions are the additional settings for the responder
+	ResponderOptions struct {
+		// contentType overrides the media type of the encoder
+		contentType string
+
+		// errorBodyFn builds the fallback response written when the encoding fails
+		errorBodyFn ErrorBodyFn
+	}
+)
+
+// NewResponderOptions creates a new ResponderOptions instance
+//
+// Parameters:
+//
+//   - contentType: overrides the media type of the encoder, empty uses the one advertised by the encoder or
+//     ContentTypeJSON
+//   - errorBodyFn: builds the fallback response written when the encoding fails, nil uses DefaultErrorBody
+//
+// Returns:
+//
+//   - *ResponderOptions: the new ResponderOptions instance
+func NewResponderOptions(
+	contentType string,
+	errorBodyFn ErrorBodyFn,
+) *ResponderOptions {
+	return &ResponderOptions{
+		contentType: contentType,
+		errorBodyFn: errorBodyFn,
+	}
+}
+
+// NewResponder creates a new Responder instance
+//
+// Parameters:
+//
+//   - encoder: the encoder used to encode the response bodies
+//   - options: the additional settings for the responder
+//
+// Returns:
+//
+//   - *Responder: the new Responder instance
+//   - error: the error if the encoder is nil
+func NewResponder(
+	encoder gojsonencoder.Encoder,
+	options *ResponderOptions,
+) (*Responder, error) {
+	// Check if the encoder is nil
+	if encoder == nil {
+		return nil, gojsonencoder.ErrNilEncoder
+	}
+
+	// Initialize the settings
+	var contentType string
+	var errorBodyFn ErrorBodyFn
+	if options != nil {
+		contentType = options.contentType
+		errorBodyFn = options.errorBodyFn
+	}
+
+	// Initialize the content type from the encoder
+	if contentType == "" {
+		contentType = EncoderContentType(encoder)
+	}
+
+	// Initialize the error body function
+	if errorBodyFn == nil {
+		errorBodyFn = DefaultErrorBody
+	}
+
+	return &Responder{
+		encoder:     encoder,
+		contentType: contentType,
+		errorBodyFn: errorBodyFn,
+	}, nil
+}
+
+// EncoderContentType returns the media type advertised by the encoder, or ContentTypeJSON if it does not
+// advertise one
+//
+// Parameters:
+//
+//   - encoder: the encoder
+//
+// Returns:
+//
+//   - string: the media type
+func EncoderContentType(encoder gojsonencoder.Encoder) string {
+	if contentTypeEncoder, ok := encoder.(gojsonencoder.ContentTypeEncoder); ok {
+		if contentType := contentTypeEncoder.ContentType(); contentType != "" {
+			return contentType
+		}
+	}
+	return ContentTypeJSON
+}
+
+// DefaultErrorBody builds a generic internal server error problem, without exposing the encoding error
+//
+// Parameters:
+//
+//   - err: the encoding error
+//
+// Returns:
+//
+//   - int: the status code
+//   - any: the body
+func DefaultErrorBody(err error) (int, any) {
+	return http.StatusInternalServerError, NewProblem(http.StatusInternalServerError, "")
+}
+
+// ContentType returns the media type of the responses
+//
+// Returns:
+//
+//   - string: the media type
+func (r Responder) ContentType() string {
+	return r.contentType
+}
+
+// Write encodes the body and writes it to the response writer with the given status code. If the encoding fails
+// before any byte is written, the fallback error response is written instead and the encoding error is returned. A
+// nil body writes only the headers and the status code
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - statusCode: The status code of the response
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func (r Responder) Write(
+	responseWriter http.ResponseWriter,
+	statusCode int,
+	body any,
+) error {
+	return r.WriteContext(context.Background(), responseWriter, statusCode, body)
+}
+
+// WriteContext encodes the body and writes it to the response writer with the given status code, as Write does,
+// until the context is done. Nothing is written once the context is done, not even the fallback error response. A
+// nil body, or a status code that does not allow a body, such as 204 and 304, writes only the headers and the status
+// code
+//
+// Parameters:
+//
+//   - ctx: The context, such as the request context
+//   - responseWriter: The response writer
+//   - statusCode: The status code of the response
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any, the cause of the context if it is done
+func (r Responder) WriteContext(
+	ctx context.Context,
+	responseWriter http.ResponseWriter,
+	statusCode int,
+	body any,
+) error {
+	// Check if the response writer is nil
+	if responseWriter == nil {
+		return ErrNilResponseWriter
+	}
+
+	// Write only the headers and the status code if there is no body, such as for the HEAD requests, or the status
+	// code does not allow one
+	if body == nil || !statusAllowsBody(statusCode) {
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+		if statusAllowsBody(statusCode) {
+			setContentHeaders(responseWriter, r.contentType)
+		}
+		responseWriter.WriteHeader(statusCode)
+		return nil
+	}
+
+	// Set the headers before the first byte, the status code is written along with the first byte of the body
+	deferredWriter := &deferredResponseWriter{ResponseWriter: responseWriter}
+	beforeWriteFn := func() error {
+		setContentHeaders(responseWriter, r.contentType)
+		deferredWriter.statusCode = statusCode
+		return nil
+	}
+
+	// Encode and write the body, the status code is written even if the body is empty
+	err := gojsonencoder.EncodeAndWriteContext(ctx, r.encoder, deferredWriter, beforeWriteFn, body)
+	if err == nil {
+		deferredWriter.writeStatusCode()
+		return nil
+	}
+	if deferredWriter.committed || context.Cause(ctx) != nil {
+		return err
+	}
+
+	// Write the fallback error response, the compression headers that may have been set are removed
+	responseWriter.Header().Del(HeaderContentEncoding)
+	errorStatusCode, errorBody := r.errorBodyFn(err)
+	errorContentType := ContentTypeJSON
+	if _, ok := errorBody.(*Problem); ok {
+		errorContentType = ContentTypeProblemJSON
+	}
+	fallbackErr := gojsonencoderjson.NewEncoder(nil).EncodeAndWrite(
+		responseWriter,
+		func() error {
+			writeHeader(responseWriter, errorContentType, errorStatusCode)
+			return nil
+		},
+		errorBody,
+	)
+	return errors.Join(err, fallbackErr)
+}
+
+// WriteResponse encodes the body with the encoder and writes it to the response writer with the given status code,
+// using the default responder settings
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - encoder: The encoder used to encode the body
+//   - statusCode: The status code of the response
+//   - body: The body to encode
+//
+// Returns:
+//
+//   - error: The error if any
+func WriteResponse(
+	responseWriter http.ResponseWriter,
+	encoder gojsonencoder.Encoder,
+	statusCode int,
+	body any,
+) error {
+	responder, err := NewResponder(encoder, nil)
+	if err != nil {
+		return err
+	}
+	return responder.Write(responseWriter, statusCode, body)
+}
+
+// statusAllowsBody returns whether a response with the status code can have a body
+//
+// Parameters:
+//
+//   - statusCode: The status code of the response
+//
+// Returns:
+//
+//   - bool: false for the informational, 204 and 304 status codes
+func statusAllowsBody(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotModified
+}
+
+// writeHeader sets the content type headers and writes the status code
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - contentType: The media type of the body
+//   - statusCode: The status code of the response
+func writeHeader(
+	responseWriter http.ResponseWriter,
+	contentType string,
+	statusCode int,
+) {
+	setContentHeaders(responseWriter, contentType)
+	responseWriter.WriteHeader(statusCode)
+}
+
+// setContentHeaders sets the content type headers
+//
+// Parameters:
+//
+//   - responseWriter: The response writer
+//   - contentType: The media type of the body
+func setContentHeaders(
+	responseWriter http.ResponseWriter,
+	contentType string,
+) {
+	header := responseWriter.Header()
+	header.Set(HeaderContentType, contentType)
+	header.Set(HeaderContentTypeOptions, "nosniff")
+}
+
+// Write writes the pending status code and the bytes of the body
+//
+// Parameters:
+//
+//   - p: The bytes to write
+//
+// Returns:
+//
+//   - int: The number of bytes written
+//   - error: The error if any
+func (d *deferredResponseWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		d.writeStatusCode()
+		d.committed = true
+	}
+	return d.ResponseWriter.Write(p)
+}
+
+// WriteHeader keeps the status code until the first byte of the body is written
+//
+// Parameters:
+//
+//   - statusCode: The status code of the response
+func (d *deferredResponseWriter) WriteHeader(statusCode int) {
+	d.statusCode = statusCode
+}
+
+// Flush writes the pending status code and flushes the response writer if it is an http.Flusher, nothing is
+// flushed before the status code is known
+func (d *deferredResponseWriter) Flush() {
+	d.writeStatusCode()
+	if !d.committed {
+		return
+	}
+	if flusher, ok := d.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped response writer, for http.ResponseController
+//
+// Returns:
+//
+//   - http.ResponseWriter: The wrapped response writer
+func (d *deferredResponseWriter) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+// writeStatusCode writes the pending status code, if any
+func (d *deferredResponseWriter) writeStatusCode() {
+	if d.statusCode != 0 {
+		d.ResponseWriter.WriteHeader(d.statusCode)
+		d.statusCode = 0
+		d.committed = true
+	}
+}
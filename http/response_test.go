@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gojsonencoder "github.com/ralvarezdev/go-json/encoder"
+	gojsonencoderjson "github.com/ralvarezdev/go-json/encoder/json"
+)
+
+func TestResponderWrite(t *testing.T) {
+	tests := []struct {
+		name            string
+		encoder         gojsonencoder.Encoder
+		statusCode      int
+		body            any
+		wantStatusCode  int
+		wantContentType string
+		wantNoBody      bool
+		wantErr         bool
+	}{
+		{
+			name:            "writes the body with the status code",
+			encoder:         gojsonencoderjson.NewEncoder(nil),
+			statusCode:      http.StatusCreated,
+			body:            map[string]int{"id": 1},
+			wantStatusCode:  http.StatusCreated,
+			wantContentType: ContentTypeJSON,
+		},
+		{
+			name:            "writes the fallback response if the encoding fails",
+			encoder:         gojsonencoderjson.NewEncoder(nil),
+			statusCode:      http.StatusCreated,
+			body:            map[string]any{"fn": func() {}},
+			wantStatusCode:  http.StatusInternalServerError,
+			wantContentType: ContentTypeProblemJSON,
+			wantErr:         true,
+		},
+		{
+			name:            "writes the fallback response if the stream encoding fails before the first byte",
+			encoder:         gojsonencoderjson.NewStreamEncoder(nil),
+			statusCode:      http.StatusCreated,
+			body:            map[string]any{"fn": func() {}},
+			wantStatusCode:  http.StatusInternalServerError,
+			wantContentType: ContentTypeProblemJSON,
+			wantErr:         true,
+		},
+		{
+			name:           "writes only the status code of the responses without content",
+			encoder:        gojsonencoderjson.NewEncoder(nil),
+			statusCode:     http.StatusNoContent,
+			wantStatusCode: http.StatusNoContent,
+			wantNoBody:     true,
+		},
+		{
+			name:           "does not encode the body of the statuses that do not allow one",
+			encoder:        gojsonencoderjson.NewEncoder(nil),
+			statusCode:     http.StatusNotModified,
+			body:           map[string]int{"id": 1},
+			wantStatusCode: http.StatusNotModified,
+			wantNoBody:     true,
+		},
+		{
+			name:            "writes the headers of the responses without a body",
+			encoder:         gojsonencoderjson.NewEncoder(nil),
+			statusCode:      http.StatusOK,
+			wantStatusCode:  http.StatusOK,
+			wantContentType: ContentTypeJSON,
+			wantNoBody:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				responder, err := NewResponder(test.encoder, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				recorder := httptest.NewRecorder()
+				err = responder.Write(recorder, test.statusCode, test.body)
+				if (err != nil) != test.wantErr {
+					t.Fatalf("error = %v, want error %t", err, test.wantErr)
+				}
+				if recorder.Code != test.wantStatusCode {
+					t.Errorf("status code = %d, want %d", recorder.Code, test.wantStatusCode)
+				}
+				if got := recorder.Header().Get(HeaderContentType); got != test.wantContentType {
+					t.Errorf("Content-Type = %q, want %q", got, test.wantContentType)
+				}
+				if test.wantNoBody && recorder.Body.Len() != 0 {
+					t.Errorf("body = %q, want no body", recorder.Body.String())
+				}
+			},
+		)
+	}
+}
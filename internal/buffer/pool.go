@@ -0,0 +1,83 @@
+package buffer
+
+import (
+	"bytes"
+	"sync"
+)
+
+var (
+	// sizeClasses are the capacities of the pooled buffers, buffers that grew beyond the last class are not pooled
+	// so a single huge payload does not stay retained
+	sizeClasses = [...]int{
+		1 << 10,
+		4 << 10,
+		16 << 10,
+		64 << 10,
+		256 << 10,
+		1 << 20,
+	}
+
+	// pools are the buffer pools, one for each size class
+	pools [len(sizeClasses)]sync.Pool
+)
+
+// Get returns an empty buffer from the pool whose capacity is at least the size hint, if the size hint is larger
+// than the last size class a new buffer is allocated. Without a size hint, the buffer is taken from the largest
+// non-empty size class, so the buffers that grew while they were used are reused
+//
+// Parameters:
+//
+//   - sizeHint: the expected size of the buffer content, 0 if unknown
+//
+// Returns:
+//
+//   - *bytes.Buffer: the empty buffer, it must be returned with Put once its content is no longer referenced
+func Get(sizeHint int) *bytes.Buffer {
+	if sizeHint <= 0 {
+		for i := len(sizeClasses) - 1; i >= 0; i-- {
+			if pooled, ok := pools[i].Get().(*bytes.Buffer); ok {
+				return pooled
+			}
+		}
+		return bytes.NewBuffer(make([]byte, 0, sizeClasses[0]))
+	}
+
+	// Take the buffer from the smallest size class that holds the size hint, or from a larger one
+	for i, sizeClass := range sizeClasses {
+		if sizeHint > sizeClass {
+			continue
+		}
+		for j := i; j < len(sizeClasses); j++ {
+			if pooled, ok := pools[j].Get().(*bytes.Buffer); ok {
+				return pooled
+			}
+		}
+		return bytes.NewBuffer(make([]byte, 0, sizeClass))
+	}
+	return bytes.NewBuffer(make([]byte, 0, sizeHint))
+}
+
+// Put resets the buffer and returns it to the pool of the largest size class it can hold, buffers larger than the
+// last size class are dropped
+//
+// Parameters:
+//
+//   - buffer: the buffer to return, it must not be used afterwards
+func Put(buffer *bytes.Buffer) {
+	if buffer == nil {
+		return
+	}
+
+	// Find the largest size class the buffer can hold
+	capacity := buffer.Cap()
+	if capacity > sizeClasses[len(sizeClasses)-1] {
+		return
+	}
+	for i := len(sizeClasses) - 1; i >= 0; i-- {
+		if capacity >= sizeClasses[i] {
+			buffer.Reset()
+			pools[i].Put(buffer)
+			return
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+type (
+	// Cache is the concurrency-safe cache of values by key, once it holds its maximum size the least recently used
+	// entry is evicted
+	Cache[K comparable, V any] struct {
+		mutex     sync.Mutex
+		maxSize   int
+		entries   map[K]*list.Element
+		order     *list.List
+		hits      uint64
+		misses    uint64
+		evictions uint64
+	}
+
+	// entry is the key and value stored in the recency list
+	entry[K comparable, V any] struct {
+		key   K
+		value V
+	}
+
+	// Stats are the statistics of a cache
+	Stats struct {
+		// Hits is the number of lookups that found their entry
+		Hits uint64
+
+		// Misses is the number of lookups that did not find their entry
+		Misses uint64
+
+		// Evictions is the number of entries evicted to keep the cache within its maximum size
+		Evictions uint64
+
+		// Size is the number of entries in the cache
+		Size int
+	}
+)
+
+// New creates a new Cache instance
+//
+// Parameters:
+//
+//   - maxSize: the maximum number of entries, 0 or a negative value means no limit
+//
+// Returns:
+//
+//   - *Cache[K, V]: the new Cache instance
+func New[K comparable, V any](maxSize int) *Cache[K, V] {
+	return &Cache[K, V]{
+		maxSize: max(maxSize, 0),
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value of the key, and marks it as the most recently used entry
+//
+// Parameters:
+//
+//   - key: the key
+//
+// Returns:
+//
+//   - V: the value, the zero value if the key is not cached
+//   - bool: whether the key is cached
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		var zero V
+		return zero, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*entry[K, V]).value, true
+}
+
+// Add stores the value of the key unless it is already cached, evicting the least recently used entry if the cache
+// is full
+//
+// Parameters:
+//
+//   - key: the key
+//   - value: the value
+//
+// Returns:
+//
+//   - V: the cached value, the one already stored if the key was added concurrently
+func (c *Cache[K, V]) Add(key K, value V) V {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Keep the value already stored, so every caller shares the same one
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*entry[K, V]).value
+	}
+
+	// Evict the least recently used entry
+	if c.maxSize > 0 && c.order.Len() >= c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[K, V]).key)
+		c.evictions++
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	return value
+}
+
+// GetOrAdd returns the value of the key, creating and storing it if it is not cached. The value is created without
+// holding the lock, so the concurrent callers may create it more than once but all of them get the stored one
+//
+// Parameters:
+//
+//   - key: the key
+//   - newValue: the function that creates the value
+//
+// Returns:
+//
+//   - V: the value
+//   - error: the error returned by the function, the value is not stored
+func (c *Cache[K, V]) GetOrAdd(key K, newValue func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := newValue()
+	if err != nil {
+		return value, err
+	}
+	return c.Add(key, value), nil
+}
+
+// Stats returns the statistics of the cache
+//
+// Returns:
+//
+//   - Stats: the statistics
+func (c *Cache[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.order.Len(),
+	}
+}
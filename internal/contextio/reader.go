@@ -0,0 +1,82 @@
+package contextio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+type (
+	// reader is the reader that stops reading once its context is done
+	reader struct {
+		ctx    context.Context
+		reader io.Reader
+	}
+
+	// readDeadliner is the interface implemented by the readers whose blocked reads can be interrupted, such as
+	// net.Conn and os.File
+	readDeadliner interface {
+		SetReadDeadline(t time.Time) error
+	}
+)
+
+// NewReader wraps the reader so it fails with the cause of the context once it is done. If the reader supports read
+// deadlines, a blocked read is also interrupted when the context is done
+//
+// Parameters:
+//
+//   - ctx: the context
+//   - r: the reader, returned as is if it is nil or the context is never done
+//
+// Returns:
+//
+//   - io.Reader: the wrapped reader
+//   - func(): the function that releases the resources of the wrapped reader, it must be called once the reading
+//     is over
+func NewReader(ctx context.Context, r io.Reader) (io.Reader, func()) {
+	if r == nil || ctx.Done() == nil {
+		return r, func() {}
+	}
+
+	// Interrupt the blocked reads when the context is done
+	wrapped := &reader{
+		ctx:    ctx,
+		reader: r,
+	}
+	deadliner, ok := r.(readDeadliner)
+	if !ok {
+		return wrapped, func() {}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = deadliner.SetReadDeadline(time.Unix(1, 0))
+	})
+	return wrapped, func() {
+		stop()
+	}
+}
+
+// Read reads from the wrapped reader unless the context is done
+//
+// Parameters:
+//
+//   - p: the buffer to read into
+//
+// Returns:
+//
+//   - int: the number of bytes read
+//   - error: the error if any, the cause of the context if it is done
+func (r *reader) Read(p []byte) (int, error) {
+	if err := context.Cause(r.ctx); err != nil {
+		return 0, err
+	}
+
+	// Report the cause of the context instead of the error of an interrupted read
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		if ctxErr := context.Cause(r.ctx); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
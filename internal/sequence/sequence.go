@@ -0,0 +1,44 @@
+package sequence
+
+import (
+	"iter"
+	"reflect"
+)
+
+const (
+	// NDJSONContentType is the media type of newline-delimited JSON
+	NDJSONContentType = "application/x-ndjson"
+
+	// JSONSeqContentType is the media type of JSON text sequences
+	JSONSeqContentType = "application/json-seq"
+
+	// RecordSeparator is the ASCII RS character that precedes each JSON text of a JSON text sequence
+	RecordSeparator = 0x1E
+)
+
+// Records returns the records of the body, the elements of a slice or an array, or the body itself
+//
+// Parameters:
+//
+//   - body: The body to get the records from
+//
+// Returns:
+//
+//   - iter.Seq2[int, any]: The sequence of indexes and records
+func Records(body any) iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		// Byte slices are encoded as a single record, as encoding/json does
+		reflectValue := reflect.ValueOf(body)
+		kind := reflectValue.Kind()
+		if (kind != reflect.Slice && kind != reflect.Array) || reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			yield(0, body)
+			return
+		}
+
+		for i := 0; i < reflectValue.Len(); i++ {
+			if !yield(i, reflectValue.Index(i).Interface()) {
+				return
+			}
+		}
+	}
+}
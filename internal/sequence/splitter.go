@@ -0,0 +1,100 @@
+package sequence
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	gojsondecoder "github.com/ralvarezdev/go-json/decoder"
+)
+
+type (
+	// Splitter reads the segments of a stream delimited by a byte. The segments larger than the maximum size are
+	// discarded while they are read, so a single oversized segment neither grows the memory nor stops the stream
+	Splitter struct {
+		reader   *bufio.Reader
+		delim    byte
+		maxSize  int
+		buffer   []byte
+		position gojsondecoder.Position
+	}
+
+	// Segment is a segment of the stream, without its delimiter
+	Segment struct {
+		// Data is the content of the segment, nil if it is too large. It is only valid until the next segment is read
+		Data []byte
+
+		// Start is the position of the first byte of the segment in the stream
+		Start gojsondecoder.Position
+
+		// TooLarge is whether the segment exceeds the maximum size, its content is discarded
+		TooLarge bool
+	}
+)
+
+// NewSplitter creates a new Splitter instance
+//
+// Parameters:
+//
+//   - reader: the reader to read the stream from
+//   - delim: the byte that delimits the segments
+//   - maxSize: the maximum size in bytes of a segment, without its delimiter
+//
+// Returns:
+//
+//   - *Splitter: the new Splitter instance
+func NewSplitter(reader io.Reader, delim byte, maxSize int) *Splitter {
+	return &Splitter{
+		reader:   bufio.NewReader(reader),
+		delim:    delim,
+		maxSize:  maxSize,
+		position: gojsondecoder.StartPosition,
+	}
+}
+
+// Next reads the next segment, up to the next delimiter or the end of the stream
+//
+// Returns:
+//
+//   - Segment: the segment
+//   - error: io.EOF once there are no more segments, or the read error
+func (s *Splitter) Next() (Segment, error) {
+	segment := Segment{Start: s.position}
+	s.buffer = s.buffer[:0]
+	for {
+		chunk, err := s.reader.ReadSlice(s.delim)
+		s.position = s.position.Advance(chunk)
+
+		// Keep the content without the delimiter, unless the segment is too large
+		content := chunk
+		if err == nil {
+			content = chunk[:len(chunk)-1]
+		}
+		if !segment.TooLarge && len(s.buffer)+len(content) > s.maxSize {
+			segment.TooLarge = true
+			s.buffer = s.buffer[:0]
+		}
+		if !segment.TooLarge {
+			s.buffer = append(s.buffer, content...)
+		}
+
+		switch {
+		case err == nil:
+		case errors.Is(err, bufio.ErrBufferFull):
+			// Read the rest of the segment
+			continue
+		case errors.Is(err, io.EOF):
+			// The stream ends without a last segment
+			if len(chunk) == 0 && len(s.buffer) == 0 && !segment.TooLarge {
+				return Segment{}, io.EOF
+			}
+		default:
+			return Segment{}, err
+		}
+
+		if !segment.TooLarge {
+			segment.Data = s.buffer
+		}
+		return segment, nil
+	}
+}